@@ -0,0 +1,141 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/gopath/path"
+
+	"strconv"
+)
+
+// Builder is a fluent front-end to the Loader which can be used to construct
+// blueprints in go without going through JSON. Each builder is scoped to a
+// path of the blueprint and all keys are dot separated paths relative to that
+// scope. Link targets are always absolute, as with the JSON front-end. eg.
+//
+//	builder := blueprint.NewBuilder()
+//	builder.Object("hello", "Printer").Set("Value", "Hello ")
+//	builder.Object("multi", "MultiHandler").Link("Handlers.0", "hello")
+//	values, err := builder.Build()
+//
+// Errors are accumulated in the underlying loader and reported by Build.
+type Builder struct {
+	loader  *Loader
+	parent  *Builder
+	current path.P
+}
+
+// NewBuilder returns a builder over a new loader which loads into a
+// map[string]interface{} object.
+func NewBuilder() *Builder {
+	return (&Loader{Values: make(map[string]interface{})}).Builder()
+}
+
+// Builder returns a builder scoped to the root of the loader's values.
+func (loader *Loader) Builder() *Builder {
+	return &Builder{loader: loader}
+}
+
+// Loader returns the underlying loader.
+func (builder *Builder) Loader() *Loader { return builder.loader }
+
+// Path returns the path of the builder's scope.
+func (builder *Builder) Path() path.P { return builder.path("") }
+
+func (builder *Builder) path(key string) path.P {
+	result := append(path.P{}, builder.current...)
+	if key == "" {
+		return result
+	}
+	return append(result, path.New(key)...)
+}
+
+// Scope returns a builder scoped to the given key.
+func (builder *Builder) Scope(key string) *Builder {
+	return &Builder{loader: builder.loader, parent: builder, current: builder.path(key)}
+}
+
+// Object creates an object of the given registered type at the given key and
+// returns a builder scoped to that object. Equivalent to the JSON "key!Type"
+// notation.
+func (builder *Builder) Object(key, typ string) *Builder {
+	builder.loader.Type(builder.path(key), typ)
+	return builder.Scope(key)
+}
+
+// End returns the builder of the parent scope or the builder itself if it's
+// scoped to the root.
+func (builder *Builder) End() *Builder {
+	if builder.parent == nil {
+		return builder
+	}
+	return builder.parent
+}
+
+// Set loads the given value at the given key. The value goes through the same
+// conversions as JSON values.
+func (builder *Builder) Set(key string, value interface{}) *Builder {
+	builder.loader.Add(builder.path(key), value)
+	return builder
+}
+
+// Type sets the type of the object at the given key without changing scope.
+func (builder *Builder) Type(key, typ string) *Builder {
+	builder.loader.Type(builder.path(key), typ)
+	return builder
+}
+
+// Link links the given key to the given absolute target. Equivalent to the JSON
+// "#key" notation.
+func (builder *Builder) Link(key, target string) *Builder {
+	builder.loader.Link(builder.path(key), path.New(target))
+	return builder
+}
+
+// Links links the consecutive elements of the slice at the given key to the
+// given absolute targets.
+func (builder *Builder) Links(key string, targets ...string) *Builder {
+	for i, target := range targets {
+		builder.loader.Link(append(builder.path(key), strconv.Itoa(i)), path.New(target))
+	}
+	return builder
+}
+
+// Call calls the given method on the object of the builder's scope. Arguments
+// created through Ref are links resolved once all the values are loaded.
+func (builder *Builder) Call(method string, args ...interface{}) *Builder {
+	builder.loader.Call(builder.path(""), method, args...)
+	return builder
+}
+
+// Ref returns a link to the given absolute target usable as an argument to
+// Call.
+func Ref(target string) path.P { return path.New(target) }
+
+// Import associates the given type name prefix with the given package path.
+func (builder *Builder) Import(prefix, pkg string) *Builder {
+	builder.loader.Import(prefix, pkg)
+	return builder
+}
+
+// Provide makes the given value available to links under the '@name' target.
+func (builder *Builder) Provide(name string, value interface{}) *Builder {
+	builder.loader.Provide(name, value)
+	return builder
+}
+
+// Build finishes loading the blueprint and returns the loaded values.
+func (builder *Builder) Build() (interface{}, error) {
+	return builder.loader.Finish()
+}
+
+// BuildAs finishes loading the blueprint and returns the value at the given
+// target as a T.
+func BuildAs[T any](builder *Builder, target string) (T, error) {
+	values, err := builder.Build()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return LookupAs[T](values, target)
+}
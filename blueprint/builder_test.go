@@ -0,0 +1,66 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	exp, err := LoadJSON([]byte(`{
+        "a!Impl": { "I": 1, "S": "x" },
+        "s!Struct": { "I": 2, "#Base": "a" },
+        "mux!Mux": {
+            "@call": [
+                { "Handle": [ "/a", "#a" ] },
+                { "Timeout": [ "5s" ] }
+            ]
+        }
+    }`))
+
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	builder := NewBuilder()
+	builder.Object("a", "Impl").Set("I", 1).Set("S", "x").
+		End().Object("s", "Struct").Set("I", 2).Link("Base", "a").
+		End().Object("mux", "Mux").Call("Handle", "/a", Ref("a")).Call("Timeout", "5s")
+
+	values, err := builder.Build()
+	if err != nil {
+		t.Fatalf("FAIL: unable to build\n%v", err)
+	}
+
+	if !reflect.DeepEqual(values, exp) {
+		t.Errorf("FAIL: %v != %v", values, exp)
+	}
+
+	builder = NewBuilder()
+	builder.Scope("s").Type("", "Struct").Object("Base", "Impl").Set("I", 3)
+
+	if s, err := BuildAs[*Struct](builder, "s"); err != nil {
+		t.Errorf("FAIL: unable to build\n%v", err)
+
+	} else if !s.Base.Eq(&Impl{I: 3}) {
+		t.Errorf("FAIL: unexpected value %v", s)
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	for json, build := range map[string]func(*Builder){
+		`{ "a!Impl": { "X": 1 } }`:    func(b *Builder) { b.Object("a", "Impl").Set("X", 1) },
+		`{ "a!Unknown": {} }`:         func(b *Builder) { b.Object("a", "Unknown") },
+		`{ "a!Impl": { "#I": "b" } }`: func(b *Builder) { b.Object("a", "Impl").Link("I", "b") },
+	} {
+		_, exp := LoadJSON([]byte(json))
+
+		builder := NewBuilder()
+		build(builder)
+
+		if _, err := builder.Build(); err == nil || exp == nil || err.Error() != exp.Error() {
+			t.Errorf("FAIL(%s): error '%v' != '%v'", json, err, exp)
+		}
+	}
+}
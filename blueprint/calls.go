@@ -0,0 +1,135 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/goklog/klog"
+	"github.com/RAttab/gopath/path"
+
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type call struct {
+	src    path.P
+	method string
+	args   []interface{}
+}
+
+// Call invokes the named method of the object at the given path with the given
+// arguments once all links are resolved in Finish. Calls on the same path are
+// executed in the order they were added which makes it possible to configure
+// objects that are driven by methods rather than fields (eg.
+// mux.Handle(pattern, handler)). Calls on different paths are executed in the
+// sorted order of their paths so that the order doesn't depend on how the
+// blueprint was decoded.
+//
+// Arguments of type path.P are links and are replaced by the value at the
+// given path while all other arguments are converted to the type of their
+// parameter. If the last value returned by the method is a non-nil error then
+// it's reported at the path of the call (eg. mux.Handle).
+func (loader *Loader) Call(src path.P, method string, args ...interface{}) {
+	klog.KPrintf("blueprint.loader.call.debug", "src=%s, method=%s, args=%v", src, method, args)
+
+	loader.calls = append(loader.calls, call{src: append(path.P{}, src...), method: method, args: args})
+}
+
+// sortedCalls returns the calls sorted by path while preserving the order in
+// which the calls of a given path were added.
+func (loader *Loader) sortedCalls() []call {
+	calls := append([]call(nil), loader.calls...)
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].src.String() < calls[j].src.String()
+	})
+	return calls
+}
+
+func (loader *Loader) call(call call) error {
+	src, err := loader.resolve(call.src, 0)
+	if err != nil {
+		return err
+	}
+
+	obj, err := loader.get(src)
+	if err != nil {
+		return err
+	}
+
+	recv := reflect.ValueOf(obj)
+	if !recv.IsValid() {
+		return fmt.Errorf("unable to call '%s' on nil value", call.method)
+	}
+
+	fn := recv.MethodByName(call.method)
+	if !fn.IsValid() {
+		var names []string
+		for i := 0; i < recv.NumMethod(); i++ {
+			names = append(names, recv.Type().Method(i).Name)
+		}
+
+		return fmt.Errorf("unknown method '%s' for '%s'%s", call.method, recv.Type(), suggest(call.method, names))
+	}
+
+	typ := fn.Type()
+	if n := len(call.args); n != typ.NumIn() && !(typ.IsVariadic() && n >= typ.NumIn()-1) {
+		return fmt.Errorf("method '%s' expects %d arguments got %d", call.method, typ.NumIn(), n)
+	}
+
+	args := make([]reflect.Value, len(call.args))
+	for i, arg := range call.args {
+		var argType reflect.Type
+		if typ.IsVariadic() && i >= typ.NumIn()-1 {
+			argType = typ.In(typ.NumIn() - 1).Elem()
+		} else {
+			argType = typ.In(i)
+		}
+
+		if args[i], err = loader.callArg(argType, arg); err != nil {
+			return fmt.Errorf("invalid argument %d: %s", i, err)
+		}
+	}
+
+	if out := fn.Call(args); len(out) > 0 {
+		if last := out[len(out)-1]; last.Type() == errorType && !last.IsNil() {
+			return last.Interface().(error)
+		}
+	}
+
+	return nil
+}
+
+func (loader *Loader) callArg(typ reflect.Type, value interface{}) (reflect.Value, error) {
+	if link, ok := value.(path.P); ok {
+		target, err := loader.resolve(link, 0)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if value, err = loader.get(target); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	value, err := loader.convert(nil, typ, value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	arg := reflect.ValueOf(value)
+	if !arg.IsValid() {
+		return reflect.Zero(typ), nil
+	}
+
+	if kind := arg.Kind(); (kind == reflect.Map || kind == reflect.Slice) && !arg.Type().AssignableTo(typ) {
+		if arg, err = decodeJSON(typ, value); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	if !arg.Type().AssignableTo(typ) {
+		return reflect.Value{}, fmt.Errorf("'%s' is not assignable to '%s'", arg.Type(), typ)
+	}
+
+	return arg, nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/gopath/path"
+
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Mux struct {
+	routes  map[string]Base
+	order   []string
+	timeout time.Duration
+}
+
+func (mux *Mux) Handle(pattern string, handler Base) {
+	if mux.routes == nil {
+		mux.routes = make(map[string]Base)
+	}
+
+	mux.routes[pattern] = handler
+	mux.order = append(mux.order, pattern)
+}
+
+func (mux *Mux) Timeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return fmt.Errorf("invalid timeout '%s'", timeout)
+	}
+
+	mux.timeout = timeout
+	return nil
+}
+
+func (mux *Mux) Order(patterns ...string) { mux.order = append(mux.order, patterns...) }
+
+func init() { Register(Mux{}) }
+
+func TestCalls(t *testing.T) {
+	values, err := LoadJSON([]byte(`{
+        "a!Impl": { "I": 1 },
+        "#b": "a",
+        "mux!Mux": {
+            "@call": [
+                { "Handle": [ "/a", "#b" ] },
+                { "Handle": [ "##b", "#a" ] },
+                { "Timeout": [ "5s" ] },
+                { "Order": [ "x", "y" ] }
+            ]
+        }
+    }`))
+
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	a := values["a"].(*Impl)
+	exp := &Mux{
+		routes:  map[string]Base{"/a": a, "#b": a},
+		order:   []string{"/a", "#b", "x", "y"},
+		timeout: 5 * time.Second,
+	}
+
+	if mux := values["mux"].(*Mux); !reflect.DeepEqual(mux, exp) {
+		t.Errorf("FAIL: %+v != %+v", mux, exp)
+	}
+
+	CheckLoadJSONError(t, `{ "mux!Mux": { "@call": [ { "Timeout": [ "-1s" ] } ] } }`, "invalid timeout '-1s' at 'mux.Timeout'")
+	CheckLoadJSONError(t, `{ "mux!Mux": { "@call": [ { "Handel": [ "/", "#mux" ] } ] } }`, "did you mean 'Handle'?")
+	CheckLoadJSONError(t, `{ "mux!Mux": { "@call": [ { "Handle": [ "/" ] } ] } }`, "expects 2 arguments got 1")
+	CheckLoadJSONError(t, `{ "mux!Mux": { "@call": [ { "Timeout": [ 1.5 ] } ] } }`, "fractional part")
+}
+
+func TestCalls_Loader(t *testing.T) {
+	loader := NewLoader()
+	loader.TestType(t, "mux", "Mux")
+	loader.Provide("impl", &Impl{I: 10})
+	loader.Call(path.New("mux"), "Handle", "/", path.New("@impl"))
+
+	values, err := loader.Finish()
+	if err != nil {
+		t.Fatalf("FAIL: unable to finish\n%v", err)
+	}
+
+	if impl := values.(map[string]interface{})["mux"].(*Mux).routes["/"].(*Impl); impl.I != 10 {
+		t.Errorf("FAIL: unexpected handler %s", impl)
+	}
+}
+
+func TestCalls_Order(t *testing.T) {
+	loader := NewLoader()
+	loader.TestType(t, "mux", "Mux")
+	loader.Link(path.New("b"), path.New("mux"))
+	loader.Link(path.New("a"), path.New("mux"))
+
+	loader.Call(path.New("b"), "Order", "b1")
+	loader.Call(path.New("a"), "Order", "a1")
+	loader.Call(path.New("b"), "Order", "b2")
+	loader.Call(path.New("a"), "Order", "a2")
+
+	values, err := loader.Finish()
+	if err != nil {
+		t.Fatalf("FAIL: unable to finish\n%v", err)
+	}
+
+	exp := []string{"a1", "a2", "b1", "b2"}
+	if order := values.(map[string]interface{})["mux"].(*Mux).order; !reflect.DeepEqual(order, exp) {
+		t.Errorf("FAIL: call order %v != %v", order, exp)
+	}
+}
+
+type CallsOuter struct{ Mid CallsMid }
+type CallsMid struct{ In CallsIn }
+type CallsIn struct{ M, Other *Mux }
+
+func init() { Register(CallsOuter{}) }
+
+func TestCalls_Siblings(t *testing.T) {
+	values, err := LoadJSON([]byte(`{
+        "x!CallsOuter": { "Mid": { "In": {
+            "M!Mux": { "@call": [ { "Order": [ "a" ] } ] },
+            "Other!Mux": { "@call": [ { "Order": [ "b" ] } ] }
+        } } }
+    }`))
+
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	in := values["x"].(*CallsOuter).Mid.In
+
+	if !reflect.DeepEqual(in.M.order, []string{"a"}) || !reflect.DeepEqual(in.Other.order, []string{"b"}) {
+		t.Errorf("FAIL: unexpected orders M=%v Other=%v", in.M.order, in.Other.order)
+	}
+}
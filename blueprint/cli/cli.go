@@ -0,0 +1,527 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+// Package cli implements the blueprint command-line tool which validates and
+// renders blueprints and documents the registered types.
+//
+// Types must be registered before a blueprint can be loaded. The blueprint
+// command loads them from go plugins (-plugin) whose init functions call
+// blueprint.Register. Alternatively, a registration set (eg. as generated by
+// blueprintgen) can be linked into a dedicated binary:
+//
+//	package main
+//
+//	import (
+//		"github.com/RAttab/goblueprint/blueprint/cli"
+//		_ "github.com/acme/handlers"
+//
+//		"os"
+//	)
+//
+//	func main() { os.Exit(cli.Main(os.Args[1:], os.Stdout, os.Stderr)) }
+//
+// Blueprints have no notion of includes or overlays: the rendered blueprint is
+// the content of a single file once types are instantiated and links are
+// resolved.
+package cli
+
+import (
+	"github.com/RAttab/goblueprint/blueprint"
+
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"plugin"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const usage = `usage: blueprint [flags] <command> [args]
+
+commands:
+  validate <file>...  load the blueprints and report their errors
+  render <file>       print the effective blueprint which loads back into the same values
+  types               list the registered types
+  explain <type>      describe the configurable fields of a type
+
+flags:
+`
+
+type plugins []string
+
+func (list *plugins) String() string { return strings.Join(*list, ",") }
+
+func (list *plugins) Set(value string) error {
+	*list = append(*list, value)
+	return nil
+}
+
+// Diagnostic is an error or warning reported by the tool. A list of
+// diagnostics is printed to stderr when the json format is selected which
+// keeps stdout reserved for the output of the command (eg. render).
+type Diagnostic struct {
+	File     string `json:"file,omitempty"`
+	Severity string `json:"severity"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+}
+
+type command struct {
+	stdout io.Writer
+	stderr io.Writer
+	format string
+	strict bool
+
+	diagnostics []Diagnostic
+}
+
+// Main runs the tool with the given arguments (excluding the program name) and
+// returns the exit status which is non-zero if any errors were reported.
+func Main(args []string, stdout, stderr io.Writer) int {
+	cmd := &command{stdout: stdout, stderr: stderr}
+
+	var paths plugins
+
+	flags := flag.NewFlagSet("blueprint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	flags.Var(&paths, "plugin", "go plugin registering types; can be repeated")
+	flags.StringVar(&cmd.format, "format", "human", "format of the diagnostics printed to stderr: human or json")
+	flags.BoolVar(&cmd.strict, "strict", false, "report unused objects and links as errors")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if cmd.format != "human" && cmd.format != "json" {
+		fmt.Fprintf(stderr, "blueprint: unknown format '%s'\n", cmd.format)
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	for _, path := range paths {
+		if _, err := plugin.Open(path); err != nil {
+			cmd.report(path, "error", err)
+		}
+	}
+
+	if len(cmd.diagnostics) == 0 {
+		args = flags.Args()
+
+		switch args[0] {
+
+		case "validate":
+			for _, file := range args[1:] {
+				cmd.load(file)
+			}
+
+		case "render":
+			if len(args) != 2 {
+				flags.Usage()
+				return 2
+			}
+
+			if values, ok := cmd.load(args[1]); ok {
+				cmd.encode(render(reflect.ValueOf(values)))
+			}
+
+		case "types":
+			cmd.types()
+
+		case "explain":
+			if len(args) != 2 {
+				flags.Usage()
+				return 2
+			}
+
+			cmd.explain(args[1])
+
+		default:
+			fmt.Fprintf(stderr, "blueprint: unknown command '%s'\n", args[0])
+			flags.Usage()
+			return 2
+		}
+	}
+
+	return cmd.flush()
+}
+
+// report records the given error and flattens Errors into one diagnostic per
+// error.
+func (cmd *command) report(file, severity string, err error) {
+	if list, ok := err.(blueprint.Errors); ok {
+		for _, err := range list {
+			cmd.report(file, severity, err)
+		}
+		return
+	}
+
+	diagnostic := Diagnostic{File: file, Severity: severity, Message: err.Error()}
+
+	var pathErr *blueprint.PathError
+	if errors.As(err, &pathErr) {
+		diagnostic.Path = pathErr.Path.String()
+		diagnostic.Message = pathErr.Err.Error()
+	}
+
+	cmd.diagnostics = append(cmd.diagnostics, diagnostic)
+}
+
+// flush prints the diagnostics and returns the exit status.
+func (cmd *command) flush() int {
+	status := 0
+	for _, diagnostic := range cmd.diagnostics {
+		if diagnostic.Severity == "error" {
+			status = 1
+		}
+	}
+
+	if cmd.format == "json" {
+		if cmd.diagnostics == nil {
+			cmd.diagnostics = []Diagnostic{}
+		}
+
+		body, _ := json.MarshalIndent(cmd.diagnostics, "", "    ")
+		fmt.Fprintf(cmd.stderr, "%s\n", body)
+		return status
+	}
+
+	for _, diagnostic := range cmd.diagnostics {
+		location := diagnostic.File
+		if diagnostic.Path != "" {
+			location += ":" + diagnostic.Path
+		}
+
+		if location != "" {
+			location += ": "
+		}
+
+		fmt.Fprintf(cmd.stderr, "%s%s: %s\n", location, diagnostic.Severity, diagnostic.Message)
+	}
+
+	return status
+}
+
+func (cmd *command) encode(value interface{}) {
+	body, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		cmd.report("", "error", err)
+		return
+	}
+
+	fmt.Fprintf(cmd.stdout, "%s\n", body)
+}
+
+func (cmd *command) load(file string) (interface{}, bool) {
+	body, err := os.ReadFile(file)
+	if err != nil {
+		cmd.report(file, "error", err)
+		return nil, false
+	}
+
+	loader := &blueprint.Loader{Values: make(map[string]interface{}), Strict: cmd.strict}
+	values, err := loader.LoadJSON(body)
+
+	cmd.report(file, "warning", loader.Warnings())
+
+	if err != nil {
+		cmd.report(file, "error", err)
+		return nil, false
+	}
+
+	return values, true
+}
+
+func (cmd *command) types() {
+	names := blueprint.DefaultRegistry.Names()
+
+	if cmd.format == "json" {
+		var types []map[string]interface{}
+
+		for _, name := range names {
+			typ, _ := blueprint.Lookup(name)
+			meta, _ := blueprint.DefaultRegistry.Meta(typ)
+			types = append(types, map[string]interface{}{"name": name, "meta": meta})
+		}
+
+		cmd.encode(types)
+		return
+	}
+
+	for _, name := range names {
+		typ, _ := blueprint.Lookup(name)
+
+		if meta, ok := blueprint.DefaultRegistry.Meta(typ); ok {
+			fmt.Fprintf(cmd.stdout, "%s %s\n", name, meta)
+		} else {
+			fmt.Fprintf(cmd.stdout, "%s\n", name)
+		}
+	}
+}
+
+func (cmd *command) explain(name string) {
+	typ, err := blueprint.Lookup(name)
+	if err != nil {
+		cmd.report("", "error", err)
+		return
+	}
+
+	meta, _ := blueprint.DefaultRegistry.Meta(typ)
+	fields := blueprint.Fields(typ)
+
+	if cmd.format == "json" {
+		var list []map[string]interface{}
+		for _, field := range fields {
+			list = append(list, map[string]interface{}{
+				"name":    field.Name,
+				"type":    field.Type.String(),
+				"options": field.Options,
+			})
+		}
+
+		cmd.encode(map[string]interface{}{"name": name, "type": typ.String(), "meta": meta, "fields": list})
+		return
+	}
+
+	fmt.Fprintf(cmd.stdout, "%s (%s)\n", name, typ)
+	if str := meta.String(); str != "" {
+		fmt.Fprintf(cmd.stdout, "    %s\n", str)
+	}
+
+	for _, field := range fields {
+		fmt.Fprintf(cmd.stdout, "\n    %s %s", field.Name, field.Type)
+
+		var options []string
+		for key, value := range field.Options {
+			if value == "" {
+				options = append(options, key)
+			} else {
+				options = append(options, key+"="+value)
+			}
+		}
+
+		if len(options) > 0 {
+			sort.Strings(options)
+			fmt.Fprintf(cmd.stdout, " [%s]", strings.Join(options, ", "))
+		}
+	}
+
+	fmt.Fprintln(cmd.stdout)
+}
+
+type renderPtr struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// renderer converts values into a JSON blueprint which loads back into the
+// same values. Each object is rendered once and subsequent references to it
+// are rendered as links using the '#' key notation. Values held by interfaces
+// are annotated with their type using the '!' key notation.
+type renderer struct {
+	seen map[renderPtr]string
+}
+
+// render renders the given values. Objects stored at the top-level are always
+// rendered under their own key and referenced through links elsewhere.
+func render(value reflect.Value) interface{} {
+	r := &renderer{seen: make(map[renderPtr]string)}
+
+	for value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Map {
+		for _, key := range value.MapKeys() {
+			elem := value.MapIndex(key)
+			for elem.Kind() == reflect.Interface && !elem.IsNil() {
+				elem = elem.Elem()
+			}
+
+			if elem.Kind() == reflect.Ptr && !elem.IsNil() {
+				r.seen[renderPtr{elem.Pointer(), elem.Type()}] = fmt.Sprint(key)
+			}
+		}
+	}
+
+	result, _ := r.render(value, "")
+	return result
+}
+
+// render returns the representation of the given value or, if the value is a
+// link, the target of the link as the second parameter. Targets are either a
+// string or an array of strings.
+func (r *renderer) render(value reflect.Value, current string) (interface{}, interface{}) {
+	if !value.IsValid() {
+		return nil, nil
+	}
+
+	if value.CanInterface() {
+		if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok && value.Kind() != reflect.Ptr {
+			if text, err := marshaler.MarshalText(); err == nil {
+				return string(text), nil
+			}
+		}
+	}
+
+	switch value.Kind() {
+
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil, nil
+		}
+
+		key := renderPtr{value.Pointer(), value.Type()}
+		if target, ok := r.seen[key]; ok && target != current {
+			return nil, target
+		}
+
+		r.seen[key] = current
+		return r.render(value.Elem(), current)
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return nil, nil
+		}
+		return r.render(value.Elem(), current)
+
+	case reflect.Struct:
+		result := make(map[string]interface{})
+		typ := value.Type()
+
+		for i := 0; i < typ.NumField(); i++ {
+			if field := value.Field(i); typ.Field(i).PkgPath == "" && !field.IsZero() {
+				r.entry(result, typ.Field(i).Name, field, current)
+			}
+		}
+
+		return result, nil
+
+	case reflect.Map:
+		result := make(map[string]interface{})
+
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+
+		for _, key := range keys {
+			r.entry(result, fmt.Sprint(key), value.MapIndex(key), current)
+		}
+
+		return result, nil
+
+	case reflect.Slice, reflect.Array:
+		return r.renderSlice(value, current)
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return nil, nil
+	}
+
+	if stringer, ok := value.Interface().(fmt.Stringer); ok && value.Type().PkgPath() != "" {
+		return stringer.String(), nil
+	}
+
+	return value.Interface(), nil
+}
+
+// renderSlice renders the elements of the given slice as an array of links if
+// they're all links or as an array if none of them are links or typed values.
+// Otherwise elements are rendered as an object keyed by their index which
+// allows links and typed values to be mixed.
+func (r *renderer) renderSlice(value reflect.Value, current string) (interface{}, interface{}) {
+	values := []interface{}{}
+	targets := []interface{}{}
+	typed := make(map[string]interface{})
+
+	for i := 0; i < value.Len(); i++ {
+		elem := value.Index(i)
+		key := strconv.Itoa(i)
+
+		result, target := r.render(elem, join(current, key))
+
+		if target != nil {
+			targets = append(targets, target)
+			typed["#"+key] = target
+		} else {
+			typed[key+typeSuffix(elem)] = result
+		}
+
+		values = append(values, result)
+	}
+
+	if len(targets) == value.Len() && len(targets) > 0 {
+		return nil, targets
+	}
+
+	if len(targets) > 0 {
+		return typed, nil
+	}
+
+	for key := range typed {
+		if strings.Contains(key, "!") {
+			return typed, nil
+		}
+	}
+
+	return values, nil
+}
+
+// entry adds the given value to the given object under the given key using
+// the '#' notation for links and the '!' notation for typed values.
+func (r *renderer) entry(obj map[string]interface{}, key string, value reflect.Value, current string) {
+	result, target := r.render(value, join(current, key))
+
+	if target != nil {
+		obj["#"+key] = target
+		return
+	}
+
+	if strings.HasPrefix(key, "#") {
+		key = "#" + key
+	}
+
+	obj[key+typeSuffix(value)] = result
+}
+
+// typeSuffix returns the '!Type' annotation of values held by interfaces.
+func typeSuffix(value reflect.Value) string {
+	if value.Kind() != reflect.Interface || value.IsNil() {
+		return ""
+	}
+
+	typ := value.Elem().Type()
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.PkgPath() == "" {
+		return ""
+	}
+
+	if short, err := blueprint.Lookup(typ.Name()); err == nil && short == typ {
+		return "!" + typ.Name()
+	}
+
+	return "!" + typ.PkgPath() + "/" + typ.Name()
+}
+
+func join(current, name string) string {
+	if current == "" {
+		return name
+	}
+	return current + "." + name
+}
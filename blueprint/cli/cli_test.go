@@ -0,0 +1,147 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package cli
+
+import (
+	"github.com/RAttab/goblueprint/blueprint"
+
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Handler interface{ Handle() }
+
+type Leaf struct {
+	Value   string `blueprint:"required"`
+	Timeout time.Duration
+	Tags    map[string]string
+}
+
+func (*Leaf) Handle() {}
+
+type Node struct {
+	Handlers []Handler
+	Primary  Handler
+	Next     *Node
+}
+
+func (*Node) Handle() {}
+
+func init() {
+	blueprint.Register(Leaf{})
+	blueprint.RegisterMeta(Node{}, blueprint.Meta{Description: "aggregates handlers"})
+}
+
+func Run(t *testing.T, status int, args ...string) (string, string) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+
+	if result := Main(args, stdout, stderr); result != status {
+		t.Errorf("FAIL(%v): status %d != %d\n%s", args, result, status, stderr)
+	}
+
+	return stdout.String(), stderr.String()
+}
+
+func WriteFile(t *testing.T, body string) string {
+	file := filepath.Join(t.TempDir(), "blueprint.json")
+	if err := os.WriteFile(file, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestRender(t *testing.T) {
+	file := WriteFile(t, `{
+        "leaf!Leaf": { "Value": "#x", "Timeout": "5s", "Tags": { "##k": "v" } },
+        "node!Node": {
+            "Handlers": { "#0": "leaf", "1!Leaf": { "Value": "y" } },
+            "#Primary": "leaf",
+            "#Next": "node"
+        },
+        "list!Node": { "#Handlers": [ "leaf", "node" ] }
+    }`)
+
+	Run(t, 0, "validate", file)
+
+	stdout, _ := Run(t, 0, "render", file)
+
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("FAIL: invalid json output: %s\n%s", err, stdout)
+	}
+
+	exp := map[string]interface{}{
+		"leaf!Leaf": map[string]interface{}{
+			"Value":   "#x",
+			"Timeout": "5s",
+			"Tags":    map[string]interface{}{"##k": "v"},
+		},
+		"list!Node": map[string]interface{}{
+			"#Handlers": []interface{}{"leaf", "node"},
+		},
+		"node!Node": map[string]interface{}{
+			"Handlers": map[string]interface{}{"#0": "leaf", "1!Leaf": map[string]interface{}{"Value": "y"}},
+			"#Primary": "leaf",
+			"#Next":    "node",
+		},
+	}
+
+	if a, b := mustMarshal(result), mustMarshal(exp); a != b {
+		t.Errorf("FAIL: %s != %s", a, b)
+	}
+
+	rendered := WriteFile(t, stdout)
+	Run(t, 0, "validate", rendered)
+
+	if again, _ := Run(t, 0, "render", rendered); again != stdout {
+		t.Errorf("FAIL: rendered blueprint doesn't round-trip\n%s\n!=\n%s", again, stdout)
+	}
+}
+
+func mustMarshal(value interface{}) string {
+	body, _ := json.Marshal(value)
+	return string(body)
+}
+
+func TestErrors(t *testing.T) {
+	file := WriteFile(t, `{ "leaf!Leaf": { "Timeout": "5s" } }`)
+
+	_, stderr := Run(t, 1, "validate", file)
+	if exp := file + ":leaf.Value: error: missing required value"; !strings.Contains(stderr, exp) {
+		t.Errorf("FAIL: '%s' doesn't contain '%s'", stderr, exp)
+	}
+
+	_, stderr = Run(t, 1, "-format", "json", "validate", file)
+
+	var diagnostics []Diagnostic
+	if err := json.Unmarshal([]byte(stderr), &diagnostics); err != nil {
+		t.Fatalf("FAIL: invalid json output: %s\n%s", err, stderr)
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Path != "leaf.Value" || diagnostics[0].Severity != "error" {
+		t.Errorf("FAIL: unexpected diagnostics: %+v", diagnostics)
+	}
+
+	Run(t, 1, "validate", filepath.Join(t.TempDir(), "missing.json"))
+	Run(t, 1, "-plugin", "missing.so", "types")
+	Run(t, 2, "unknown")
+}
+
+func TestTypes(t *testing.T) {
+	stdout, _ := Run(t, 0, "types")
+	if !strings.HasPrefix(stdout, "Leaf\n") || !strings.Contains(stdout, "Node - aggregates handlers") {
+		t.Errorf("FAIL: unexpected types:\n%s", stdout)
+	}
+
+	stdout, _ = Run(t, 0, "explain", "Leaf")
+	if !strings.Contains(stdout, "Value string [required]") {
+		t.Errorf("FAIL: unexpected explanation:\n%s", stdout)
+	}
+
+	Run(t, 1, "explain", "Leef")
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// coerce parses the given string into a value of the given type. This is used
+// by the loader's coercion mode to configure typed fields from sources which
+// only provide strings (eg. environment variables and command line flags).
+//
+// Booleans accept true/yes/on/1 and false/no/off/0, numbers accept any of the
+// numeric kinds, slices are parsed from comma separated elements and maps are
+// parsed from comma separated k=v pairs.
+func coerce(typ reflect.Type, str string) (interface{}, error) {
+	switch {
+
+	case typ.Kind() == reflect.String:
+		return reflect.ValueOf(str).Convert(typ).Interface(), nil
+
+	case typ.Kind() == reflect.Bool:
+		switch strings.ToLower(strings.TrimSpace(str)) {
+		case "true", "yes", "on", "1":
+			return reflect.ValueOf(true).Convert(typ).Interface(), nil
+		case "false", "no", "off", "0":
+			return reflect.ValueOf(false).Convert(typ).Interface(), nil
+		}
+		return nil, fmt.Errorf("invalid boolean '%s'", str)
+
+	case isNumeric(typ):
+		return convertNumber(typ, json.Number(strings.TrimSpace(str)))
+
+	case typ.Kind() == reflect.Slice:
+		obj := reflect.MakeSlice(typ, 0, 0)
+
+		for _, item := range splitList(str) {
+			elem, err := coerceValue(typ.Elem(), item)
+			if err != nil {
+				return nil, err
+			}
+			obj = reflect.Append(obj, elem)
+		}
+
+		return obj.Interface(), nil
+
+	case typ.Kind() == reflect.Map:
+		obj := reflect.MakeMap(typ)
+
+		for _, item := range splitList(str) {
+			i := strings.Index(item, "=")
+			if i < 0 {
+				return nil, fmt.Errorf("invalid map entry '%s'; expected k=v", item)
+			}
+
+			key, err := coerceValue(typ.Key(), strings.TrimSpace(item[:i]))
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := coerceValue(typ.Elem(), strings.TrimSpace(item[i+1:]))
+			if err != nil {
+				return nil, err
+			}
+
+			obj.SetMapIndex(key, value)
+		}
+
+		return obj.Interface(), nil
+	}
+
+	return str, nil
+}
+
+// coerceValue converts the given string using the registered converters and
+// falls back on coerce if the string was left as is.
+func coerceValue(typ reflect.Type, str string) (reflect.Value, error) {
+	value, err := convert(typ, str)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if str, ok := value.(string); ok {
+		if value, err = coerce(typ, str); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	obj := reflect.ValueOf(value)
+	if !obj.Type().AssignableTo(typ) {
+		return reflect.Value{}, fmt.Errorf("unable to coerce '%s' into '%s'", str, typ)
+	}
+
+	return obj, nil
+}
+
+func splitList(str string) []string {
+	if str = strings.TrimSpace(str); str == "" {
+		return nil
+	}
+
+	items := strings.Split(str, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return items
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/gopath/path"
+
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Flags struct {
+	Enabled  bool
+	Count    int
+	Ratio    float64
+	Names    []string
+	Ports    []uint16
+	Timeouts []time.Duration
+	Weights  map[string]int
+	Level    Level
+}
+
+func TestCoerce(t *testing.T) {
+	flags := &Flags{}
+	loader := &Loader{Values: flags, Coerce: true}
+
+	loader.TestAdd(t, "Enabled", "yes")
+	loader.TestAdd(t, "Count", "42")
+	loader.TestAdd(t, "Ratio", "0.5")
+	loader.TestAdd(t, "Names", "a, b,c")
+	loader.TestAdd(t, "Ports", "80,443")
+	loader.TestAdd(t, "Timeouts", "1s,1m")
+	loader.TestAdd(t, "Weights", "a=1, b=2")
+	loader.TestAdd(t, "Level", "Info")
+
+	if _, err := loader.Finish(); err != nil {
+		t.Fatalf("FAIL: unable to finish\n%v", err)
+	}
+
+	exp := &Flags{
+		Enabled:  true,
+		Count:    42,
+		Ratio:    0.5,
+		Names:    []string{"a", "b", "c"},
+		Ports:    []uint16{80, 443},
+		Timeouts: []time.Duration{time.Second, time.Minute},
+		Weights:  map[string]int{"a": 1, "b": 2},
+		Level:    Info,
+	}
+
+	if !reflect.DeepEqual(flags, exp) {
+		t.Errorf("FAIL: %+v != %+v", flags, exp)
+	}
+
+	for _, test := range []struct{ src, value string }{
+		{"Enabled", "maybe"},
+		{"Count", "4.2"},
+		{"Ports", "80,65536"},
+		{"Weights", "a:1"},
+	} {
+		loader := &Loader{Values: &Flags{}, Coerce: true}
+		if loader.Add(path.New(test.src), test.value); len(loader.errors) == 0 {
+			t.Errorf("FAIL: expected error when coercing '%s' into '%s'", test.value, test.src)
+		}
+	}
+
+	loader = &Loader{Values: &Flags{}}
+	if loader.Add(path.New("Count"), "42"); len(loader.errors) == 0 {
+		t.Errorf("FAIL: expected error when coercion is disabled")
+	}
+}
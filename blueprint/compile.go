@@ -0,0 +1,497 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"go/format"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CompileOptions controls the code generated by Compile.
+type CompileOptions struct {
+
+	// Package is the name of the package of the generated code.
+	Package string
+
+	// PackagePath is the import path of the package of the generated code.
+	// Types of that package are referenced without qualifier and their
+	// unexported fields can be set.
+	PackagePath string
+
+	// Func is the name of the generated function which defaults to Build.
+	Func string
+}
+
+// Compile generates go source for a function which constructs the given value
+// (eg. as returned by LoadJSON) using plain literals and assignments instead of
+// reflection. Pointers, maps and slices reachable from multiple places are
+// shared in the constructed value in the same way as links are in the loaded
+// value, cycles included. Structs with unexported fields outside of the
+// generated package can only be compiled if they implement
+// encoding.TextMarshaler and encoding.TextUnmarshaler; other values which can't
+// be expressed as literals (eg. channels or functions) are reported as errors.
+func Compile(value interface{}, opts CompileOptions) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, fmt.Errorf("missing package name")
+	}
+
+	if opts.Func == "" {
+		opts.Func = "Build"
+	}
+
+	compiler := &compiler{
+		CompileOptions: opts,
+		imports:        make(map[string]string),
+		vars:           make(map[compiledPtr]string),
+		seen:           make(map[compiledPtr]int),
+	}
+
+	root := reflect.ValueOf(value)
+	if !root.IsValid() {
+		return nil, fmt.Errorf("unable to compile nil value")
+	}
+
+	compiler.discover(root)
+
+	var refs []reflect.Value
+	for _, ref := range compiler.refs {
+		if key := compiledPtrOf(ref); compiler.seen[key] > 1 {
+			compiler.vars[key] = "v" + strconv.Itoa(len(compiler.vars))
+			refs = append(refs, ref)
+		}
+	}
+
+	body := new(bytes.Buffer)
+	rootType := compiler.typeName(root.Type())
+
+	for _, ptr := range compiler.ptrs {
+		fmt.Fprintf(body, "\t%s := new(%s)\n", compiler.vars[compiledPtrOf(ptr)], compiler.typeName(ptr.Type().Elem()))
+	}
+
+	for _, ref := range refs {
+		fmt.Fprintf(body, "\t%s := make(%s, %d)\n", compiler.vars[compiledPtrOf(ref)], compiler.typeName(ref.Type()), ref.Len())
+	}
+
+	for _, ptr := range compiler.ptrs {
+		if elem := ptr.Elem(); !elem.IsZero() {
+			fmt.Fprintf(body, "\t*%s = %s\n", compiler.vars[compiledPtrOf(ptr)], compiler.expr(elem, false))
+		}
+	}
+
+	for _, ref := range refs {
+		name := compiler.vars[compiledPtrOf(ref)]
+
+		if ref.Kind() == reflect.Map {
+			for _, key := range sortedKeys(ref) {
+				fmt.Fprintf(body, "\t%s[%s] = %s\n", name, compiler.expr(key, false), compiler.expr(ref.MapIndex(key), false))
+			}
+			continue
+		}
+
+		for i := 0; i < ref.Len(); i++ {
+			if elem := ref.Index(i); !elem.IsZero() {
+				fmt.Fprintf(body, "\t%s[%d] = %s\n", name, i, compiler.expr(elem, false))
+			}
+		}
+	}
+
+	fmt.Fprintf(body, "\treturn %s\n", compiler.expr(root, false))
+
+	if compiler.text {
+		compiler.importPkg("encoding")
+	}
+
+	if len(compiler.errors) > 0 {
+		return nil, compiler.errors
+	}
+
+	buffer := new(bytes.Buffer)
+	fmt.Fprintf(buffer, "// Code generated by blueprint.Compile; DO NOT EDIT.\n\n")
+	fmt.Fprintf(buffer, "package %s\n\n", opts.Package)
+
+	if len(compiler.imports) > 0 {
+		var paths []string
+		for path := range compiler.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		buffer.WriteString("import (\n")
+		for _, path := range paths {
+			if alias := compiler.imports[path]; alias != path[strings.LastIndex(path, "/")+1:] {
+				fmt.Fprintf(buffer, "\t%s %q\n", alias, path)
+			} else {
+				fmt.Fprintf(buffer, "\t%q\n", path)
+			}
+		}
+		buffer.WriteString(")\n\n")
+	}
+
+	fmt.Fprintf(buffer, "// %s constructs the compiled blueprint.\n", opts.Func)
+	fmt.Fprintf(buffer, "func %s() %s {\n%s}\n", opts.Func, rootType, body)
+
+	if compiler.text {
+		fmt.Fprintf(buffer, compiledTextHelper, compiler.textHelper())
+	}
+
+	return format.Source(buffer.Bytes())
+}
+
+const compiledTextHelper = `
+func %s[T any, P interface {
+	*T
+	encoding.TextUnmarshaler
+}](text string) T {
+	var value T
+	if err := P(&value).UnmarshalText([]byte(text)); err != nil {
+		panic(err)
+	}
+	return value
+}
+`
+
+// textHelper returns the name of the text unmarshalling helper which is
+// derived from the name of the generated function so that multiple compiled
+// blueprints can live in the same package.
+func (compiler *compiler) textHelper() string {
+	return strings.ToLower(compiler.Func[:1]) + compiler.Func[1:] + "UnmarshalText"
+}
+
+// compiledPtr identifies a pointer, map or slice. Slices are only considered
+// identical if they also have the same length.
+type compiledPtr struct {
+	addr uintptr
+	typ  reflect.Type
+	len  int
+}
+
+func compiledPtrOf(ptr reflect.Value) compiledPtr {
+	if ptr.Kind() == reflect.Slice {
+		return compiledPtr{ptr.Pointer(), ptr.Type(), ptr.Len()}
+	}
+	return compiledPtr{ptr.Pointer(), ptr.Type(), 0}
+}
+
+type compiler struct {
+	CompileOptions
+
+	imports map[string]string
+	vars    map[compiledPtr]string
+	ptrs    []reflect.Value
+	seen    map[compiledPtr]int
+	refs    []reflect.Value
+	text    bool
+	errors  Errors
+}
+
+func (compiler *compiler) fail(format string, args ...interface{}) string {
+	compiler.errors = append(compiler.errors, fmt.Errorf(format, args...))
+	return "nil"
+}
+
+// discover assigns a variable to every pointer reachable from the given value
+// and counts the references to every map and slice.
+func (compiler *compiler) discover(value reflect.Value) {
+	switch value.Kind() {
+
+	case reflect.Ptr:
+		if value.IsNil() {
+			return
+		}
+
+		key := compiledPtrOf(value)
+		if _, ok := compiler.vars[key]; ok {
+			return
+		}
+
+		compiler.vars[key] = "v" + strconv.Itoa(len(compiler.ptrs))
+		compiler.ptrs = append(compiler.ptrs, value)
+		compiler.discover(value.Elem())
+
+	case reflect.Interface:
+		if !value.IsNil() {
+			compiler.discover(value.Elem())
+		}
+
+	case reflect.Struct:
+		if compiler.opaque(value) {
+			return
+		}
+
+		for i := 0; i < value.NumField(); i++ {
+			compiler.discover(value.Field(i))
+		}
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && !compiler.ref(value) {
+			return
+		}
+
+		for i := 0; i < value.Len(); i++ {
+			compiler.discover(value.Index(i))
+		}
+
+	case reflect.Map:
+		if !compiler.ref(value) {
+			return
+		}
+
+		for _, key := range sortedKeys(value) {
+			compiler.discover(key)
+			compiler.discover(value.MapIndex(key))
+		}
+	}
+}
+
+// ref counts a reference to the given map or slice and returns true if it's
+// the first one. Nil and empty values can't be observed as shared and are
+// ignored.
+func (compiler *compiler) ref(value reflect.Value) bool {
+	if value.IsNil() || value.Len() == 0 {
+		return true
+	}
+
+	key := compiledPtrOf(value)
+	if compiler.seen[key]++; compiler.seen[key] > 1 {
+		return false
+	}
+
+	compiler.refs = append(compiler.refs, value)
+	return true
+}
+
+// sortedKeys returns the keys of the given map in a deterministic order such
+// that the generated code is stable.
+func sortedKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprintf("%v", keys[i]) < fmt.Sprintf("%v", keys[j])
+	})
+	return keys
+}
+
+// opaque returns true if the given struct value has unexported fields which
+// can't be set by the generated code.
+func (compiler *compiler) opaque(value reflect.Value) bool {
+	typ := value.Type()
+	if typ.PkgPath() == compiler.PackagePath && typ.Name() != "" {
+		return false
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).PkgPath != "" && !value.Field(i).IsZero() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (compiler *compiler) importPkg(path string) string {
+	if alias, ok := compiler.imports[path]; ok {
+		return alias
+	}
+
+	base := path[strings.LastIndex(path, "/")+1:]
+	base = strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, base)
+
+	taken := func(alias string) bool {
+		for _, other := range compiler.imports {
+			if other == alias {
+				return true
+			}
+		}
+		return false
+	}
+
+	alias := base
+	for i := 2; taken(alias); i++ {
+		alias = base + strconv.Itoa(i)
+	}
+
+	compiler.imports[path] = alias
+	return alias
+}
+
+func (compiler *compiler) typeName(typ reflect.Type) string {
+	if name := typ.Name(); name != "" {
+		switch {
+
+		case typ.PkgPath() == "":
+			return name
+
+		case strings.Contains(name, "["):
+			return compiler.fail("generic type '%s' is not supported", typ)
+
+		case typ.PkgPath() == compiler.PackagePath:
+			return name
+
+		case !isExported(name):
+			return compiler.fail("unexported type '%s' can't be referenced", typ)
+
+		default:
+			return compiler.importPkg(typ.PkgPath()) + "." + name
+		}
+	}
+
+	switch typ.Kind() {
+
+	case reflect.Ptr:
+		return "*" + compiler.typeName(typ.Elem())
+
+	case reflect.Slice:
+		return "[]" + compiler.typeName(typ.Elem())
+
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", typ.Len(), compiler.typeName(typ.Elem()))
+
+	case reflect.Map:
+		return fmt.Sprintf("map[%s]%s", compiler.typeName(typ.Key()), compiler.typeName(typ.Elem()))
+
+	case reflect.Interface:
+		if typ.NumMethod() == 0 {
+			return "interface{}"
+		}
+	}
+
+	return compiler.fail("unnamed type '%s' is not supported", typ)
+}
+
+func isExported(name string) bool {
+	return name != "" && strings.ToUpper(name[:1]) == name[:1]
+}
+
+// expr returns the go expression of the given value. Typed expressions are
+// required when the value is stored in an interface where untyped constants
+// would take their default type.
+func (compiler *compiler) expr(value reflect.Value, typed bool) string {
+	typ := value.Type()
+
+	switch value.Kind() {
+
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		if value.IsNil() {
+			if typed {
+				return "(" + compiler.typeName(typ) + ")(nil)"
+			}
+			return "nil"
+		}
+	}
+
+	var literal string
+
+	switch value.Kind() {
+
+	case reflect.Bool:
+		literal = strconv.FormatBool(value.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		literal = strconv.FormatInt(value.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		literal = strconv.FormatUint(value.Uint(), 10)
+
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(value.Float()) || math.IsInf(value.Float(), 0) {
+			return compiler.fail("non-finite number '%v' is not supported", value.Float())
+		}
+		literal = strconv.FormatFloat(value.Float(), 'g', -1, typ.Bits())
+
+	case reflect.String:
+		literal = strconv.Quote(value.String())
+
+	case reflect.Ptr:
+		return compiler.vars[compiledPtrOf(value)]
+
+	case reflect.Interface:
+		return compiler.expr(value.Elem(), true)
+
+	case reflect.Struct:
+		return compiler.structExpr(value)
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice {
+			if name, ok := compiler.vars[compiledPtrOf(value)]; ok {
+				return name
+			}
+		}
+
+		var elems []string
+		for i := 0; i < value.Len(); i++ {
+			elems = append(elems, compiler.expr(value.Index(i), false))
+		}
+		return compiler.typeName(typ) + "{" + strings.Join(elems, ", ") + "}"
+
+	case reflect.Map:
+		if name, ok := compiler.vars[compiledPtrOf(value)]; ok {
+			return name
+		}
+
+		var elems []string
+		for _, key := range sortedKeys(value) {
+			elems = append(elems, compiler.expr(key, false)+": "+compiler.expr(value.MapIndex(key), false))
+		}
+		if len(elems) == 0 {
+			return compiler.typeName(typ) + "{}"
+		}
+		return compiler.typeName(typ) + "{\n" + strings.Join(elems, ",\n") + ",\n}"
+
+	default:
+		return compiler.fail("value of type '%s' is not supported", typ)
+	}
+
+	if typed && typ != reflect.TypeOf(false) && typ != reflect.TypeOf("") && typ != reflect.TypeOf(0) {
+		return compiler.typeName(typ) + "(" + literal + ")"
+	}
+
+	return literal
+}
+
+func (compiler *compiler) structExpr(value reflect.Value) string {
+	typ := value.Type()
+
+	if compiler.opaque(value) {
+		var marshaler encoding.TextMarshaler
+		ok := value.CanInterface()
+		if ok {
+			marshaler, ok = value.Interface().(encoding.TextMarshaler)
+		}
+
+		if !ok || !reflect.PtrTo(typ).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) {
+			return compiler.fail("unexported fields of '%s' can't be compiled", typ)
+		}
+
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return compiler.fail("unable to marshal '%s': %s", typ, err)
+		}
+
+		compiler.text = true
+		return fmt.Sprintf("%s[%s](%s)", compiler.textHelper(), compiler.typeName(typ), strconv.Quote(string(text)))
+	}
+
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		if field := value.Field(i); !field.IsZero() {
+			fields = append(fields, typ.Field(i).Name+": "+compiler.expr(field, false))
+		}
+	}
+
+	if len(fields) == 0 {
+		return compiler.typeName(typ) + "{}"
+	}
+
+	return compiler.typeName(typ) + "{\n" + strings.Join(fields, ",\n") + ",\n}"
+}
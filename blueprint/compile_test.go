@@ -0,0 +1,64 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func CheckCompileError(t *testing.T, value interface{}, exp string) {
+	if _, err := Compile(value, CompileOptions{Package: "x"}); err == nil {
+		t.Errorf("FAIL: expected error for %v", value)
+
+	} else if !strings.Contains(err.Error(), exp) {
+		t.Errorf("FAIL: error '%s' doesn't contain '%s'", err, exp)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	mux := &Mux{}
+	mux.Order("a")
+
+	CheckCompileError(t, map[string]interface{}{"mux": mux}, "unexported fields of 'blueprint.Mux' can't be compiled")
+	CheckCompileError(t, map[string]interface{}{"c": make(chan int)}, "value of type 'chan int' is not supported")
+	CheckCompileError(t, struct{ I int }{1}, "unnamed type")
+	CheckCompileError(t, nil, "unable to compile nil value")
+
+	if _, err := Compile(map[string]interface{}{"mux": &Mux{}}, CompileOptions{Package: "x"}); err != nil {
+		t.Errorf("FAIL: unexpected error for zero unexported fields: %s", err)
+	}
+}
+
+func TestCompile_EmptyMaps(t *testing.T) {
+	values, err := LoadJSON([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	for _, value := range []interface{}{
+		values,
+		map[string]interface{}{"a": map[string]int{}},
+	} {
+		if body, err := Compile(value, CompileOptions{Package: "x"}); err != nil {
+			t.Errorf("FAIL(%v): unexpected error: %s", value, err)
+
+		} else if !strings.Contains(string(body), "{}") {
+			t.Errorf("FAIL(%v): missing empty literal\n%s", value, body)
+		}
+	}
+}
+
+func TestCompile_TextHelper(t *testing.T) {
+	value := map[string]interface{}{"t": time.Date(2014, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	body, err := Compile(value, CompileOptions{Package: "x", Func: "Other"})
+	if err != nil {
+		t.Fatalf("FAIL: unexpected error: %s", err)
+	}
+
+	if !strings.Contains(string(body), "func otherUnmarshalText[") {
+		t.Errorf("FAIL: text helper not named after the function\n%s", body)
+	}
+}
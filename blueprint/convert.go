@@ -5,7 +5,12 @@ package blueprint
 import (
 	"github.com/RAttab/goklog/klog"
 
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
 	"reflect"
+	"strconv"
 	"sync"
 )
 
@@ -29,7 +34,22 @@ var converters map[reflect.Type]Converter
 var convertersMutex sync.Mutex
 
 // RegisterConverter makes the given converter for the type of the given object.
+// Numbers are passed to the converter as float64 values like encoding/json
+// would. Duplicate registrations are fatal; use TryRegisterConverter to handle
+// them.
 func RegisterConverter(obj interface{}, conv Converter) {
+	if err := TryRegisterConverter(obj, conv); err != nil {
+		klog.KFatalf("blueprint.converters.register.error", "%s", err)
+	}
+}
+
+// TryRegisterConverter makes the given converter for the type of the given
+// object. Returns a DuplicateError if a converter already exists for the type.
+func TryRegisterConverter(obj interface{}, conv Converter) error {
+	if obj == nil {
+		return ErrNilRegistration
+	}
+
 	convertersMutex.Lock()
 	defer convertersMutex.Unlock()
 
@@ -40,23 +60,177 @@ func RegisterConverter(obj interface{}, conv Converter) {
 	}
 
 	if _, ok := converters[typ]; ok {
-		klog.KFatalf("blueprint.converters.register.error", "duplicate converters for type '%s'", typ)
+		return &DuplicateError{Kind: "converter", Name: typ.String()}
 	}
 
 	converters[typ] = conv
+	return nil
 }
 
-func convert(typ reflect.Type, value interface{}) (interface{}, error) {
+// UnregisterConverter removes the converter for the type of the given object.
+// Returns false if no converters were registered for the type.
+func UnregisterConverter(obj interface{}) bool {
+	convertersMutex.Lock()
+	defer convertersMutex.Unlock()
+
+	typ := reflect.TypeOf(obj)
+
+	_, ok := converters[typ]
+	delete(converters, typ)
+
+	return ok
+}
+
+func lookupConverter(typ reflect.Type) (Converter, bool) {
 	convertersMutex.Lock()
+	defer convertersMutex.Unlock()
+
+	conv, ok := converters[typ]
+	return conv, ok
+}
+
+func convert(typ reflect.Type, value interface{}) (interface{}, error) {
+	if reflect.TypeOf(value) == typ {
+		return value, nil
+	}
+
+	if num, ok := value.(json.Number); ok {
+		if isNumeric(typ) || typ.Kind() == reflect.Interface {
+			return convertNumber(typ, num)
+		}
+	}
+
+	if conv, ok := lookupConverter(typ); ok {
+		if num, ok := value.(json.Number); ok {
+			value, _ = strconv.ParseFloat(string(num), 64)
+		}
+		return conv.Convert(value)
+	}
+
+	if name, ok := value.(string); ok && typ.Kind() == reflect.Func {
+		return DefaultFunctions.convert(typ, name)
+	}
 
-	var err error
+	if str, ok := value.(string); ok && implements(typ, textUnmarshalerType) {
+		obj := newUnmarshaler(typ)
+		err := obj.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+		return unmarshaled(typ, obj), err
+	}
 
-	if reflect.TypeOf(value) != typ {
-		if conv, ok := converters[typ]; ok {
-			value, err = conv.Convert(value)
+	if implements(typ, jsonUnmarshalerType) {
+		body, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
 		}
+
+		obj := newUnmarshaler(typ)
+		err = obj.Interface().(json.Unmarshaler).UnmarshalJSON(body)
+		return unmarshaled(typ, obj), err
+	}
+
+	return value, nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// implements returns true if a pointer to the given type or, if the given type
+// is a pointer, the type itself implements the given interface.
+func implements(typ, iface reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		return typ.Implements(iface)
+	}
+	return typ.Kind() != reflect.Interface && reflect.PtrTo(typ).Implements(iface)
+}
+
+// newUnmarshaler returns a pointer to a newly allocated object of the given
+// type or of its element type if the given type is a pointer.
+func newUnmarshaler(typ reflect.Type) reflect.Value {
+	if typ.Kind() == reflect.Ptr {
+		return reflect.New(typ.Elem())
+	}
+	return reflect.New(typ)
+}
+
+func unmarshaled(typ reflect.Type, obj reflect.Value) interface{} {
+	if typ.Kind() == reflect.Ptr {
+		return obj.Interface()
+	}
+	return obj.Elem().Interface()
+}
+
+func isNumeric(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Float32, reflect.Float64:
+	default:
+		return false
+	}
+	return true
+}
+
+// convertNumber converts the given number into the given numeric type without
+// any loss of precision. Numbers assigned to interfaces are converted to
+// float64 to remain consistent with encoding/json.
+func convertNumber(typ reflect.Type, num json.Number) (interface{}, error) {
+	if typ.Kind() == reflect.Interface {
+		return num.Float64()
+	}
+
+	obj := reflect.New(typ).Elem()
+
+	switch typ.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := parseInteger(typ, num)
+		if err != nil {
+			return nil, err
+		}
+
+		if !n.IsInt64() || obj.OverflowInt(n.Int64()) {
+			return nil, fmt.Errorf("number '%s' overflows '%s'", num, typ)
+		}
+
+		obj.SetInt(n.Int64())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := parseInteger(typ, num)
+		if err != nil {
+			return nil, err
+		}
+
+		if n.Sign() < 0 {
+			return nil, fmt.Errorf("negative number '%s' can't be assigned to '%s'", num, typ)
+		}
+
+		if !n.IsUint64() || obj.OverflowUint(n.Uint64()) {
+			return nil, fmt.Errorf("number '%s' overflows '%s'", num, typ)
+		}
+
+		obj.SetUint(n.Uint64())
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(string(num), typ.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("number '%s' overflows '%s'", num, typ)
+		}
+
+		obj.SetFloat(f)
+	}
+
+	return obj.Interface(), nil
+}
+
+func parseInteger(typ reflect.Type, num json.Number) (*big.Int, error) {
+	rat, ok := new(big.Rat).SetString(string(num))
+	if !ok {
+		return nil, fmt.Errorf("invalid number '%s'", num)
+	}
+
+	if !rat.IsInt() {
+		return nil, fmt.Errorf("number '%s' has a fractional part and can't be assigned to '%s'", num, typ)
 	}
 
-	convertersMutex.Unlock()
-	return value, err
+	return rat.Num(), nil
 }
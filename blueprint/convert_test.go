@@ -0,0 +1,120 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+)
+
+type Point struct{ X, Y int }
+
+func (point *Point) UnmarshalJSON(body []byte) error {
+	var coords []int
+	if err := json.Unmarshal(body, &coords); err != nil {
+		return err
+	}
+
+	if len(coords) != 2 {
+		return fmt.Errorf("expected 2 coordinates got %d", len(coords))
+	}
+
+	point.X, point.Y = coords[0], coords[1]
+	return nil
+}
+
+type Unmarshalers struct {
+	IP    net.IP
+	Big   *big.Int
+	Point Point
+}
+
+func init() { Register(Unmarshalers{}) }
+
+func TestConvert_Unmarshalers(t *testing.T) {
+	num, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	CheckLoadJSON(t, `{
+        "u!Unmarshalers": {
+            "IP": "10.0.0.1",
+            "Big": "123456789012345678901234567890",
+            "Point": [ 1, 2 ]
+        }
+    }`, map[string]interface{}{
+		"u": &Unmarshalers{IP: net.ParseIP("10.0.0.1"), Big: num, Point: Point{X: 1, Y: 2}},
+	})
+
+	CheckLoadJSONError(t, `{ "u!Unmarshalers": { "IP": "10.0.0" } }`, "invalid IP address")
+	CheckLoadJSONError(t, `{ "u!Unmarshalers": { "Point": [ 1 ] } }`, "expected 2 coordinates")
+}
+
+type Numbers struct {
+	I8  int8
+	I64 int64
+	U16 uint16
+	U64 uint64
+	F32 float32
+	F64 float64
+}
+
+func init() { Register(Numbers{}) }
+
+func TestConvert_Numbers(t *testing.T) {
+	CheckLoadJSON(t, `{
+        "n!Numbers": {
+            "I8": -128,
+            "I64": 9007199254740993,
+            "U16": 65535,
+            "U64": 18446744073709551615,
+            "F32": 1.5,
+            "F64": 1e300
+        },
+        "e!Numbers": { "I64": 1e3 }
+    }`, map[string]interface{}{
+		"n": &Numbers{
+			I8:  -128,
+			I64: 9007199254740993,
+			U16: 65535,
+			U64: 18446744073709551615,
+			F32: 1.5,
+			F64: 1e300,
+		},
+		"e": &Numbers{I64: 1000},
+	})
+
+	CheckLoadJSONError(t, `{ "n!Numbers": { "I8": 128 } }`, "number '128' overflows 'int8' at 'n.I8'")
+	CheckLoadJSONError(t, `{ "n!Numbers": { "U64": 18446744073709551616 } }`, "overflows 'uint64'")
+	CheckLoadJSONError(t, `{ "n!Numbers": { "U16": -1 } }`, "negative number '-1'")
+	CheckLoadJSONError(t, `{ "n!Numbers": { "I64": 1.5 } }`, "has a fractional part")
+	CheckLoadJSONError(t, `{ "n!Numbers": { "F32": 1e39 } }`, "overflows 'float32'")
+}
+
+type Vector struct{ X, Y float64 }
+
+type VectorHolder struct{ V Vector }
+
+func init() {
+	Register(VectorHolder{})
+
+	RegisterConverter(Vector{}, ConverterFn(func(value interface{}) (interface{}, error) {
+		if num, ok := value.(float64); ok {
+			return Vector{X: num, Y: num}, nil
+		}
+		return nil, fmt.Errorf("expected float64 got '%T'", value)
+	}))
+}
+
+func TestConvert_ConverterNumbers(t *testing.T) {
+	CheckLoadJSON(t, `{ "p!VectorHolder": { "V": 1.5 } }`, map[string]interface{}{
+		"p": &VectorHolder{V: Vector{X: 1.5, Y: 1.5}},
+	})
+}
+
+func TestConvert_TrailingData(t *testing.T) {
+	CheckLoadJSONError(t, `{ "a": 1 }}`, "unexpected data after top-level JSON value")
+	CheckLoadJSONError(t, `{ "a": 1 }]`, "unexpected data after top-level JSON value")
+	CheckLoadJSONError(t, `{ "a": 1 } { "b": 2 }`, "unexpected data after top-level JSON value")
+}
@@ -3,6 +3,8 @@
 package blueprint
 
 import (
+	"fmt"
+	"os"
 	"time"
 )
 
@@ -14,6 +16,54 @@ func DurationConverter(value interface{}) (interface{}, error) {
 	return value, nil
 }
 
+// MillisConverter converts duration string values into an integer number of
+// milliseconds. Durations which aren't a whole number of milliseconds are
+// rejected.
+func MillisConverter(value interface{}) (interface{}, error) {
+	return convertDuration(value, time.Millisecond, "milliseconds")
+}
+
+// SecondsConverter converts duration string values into an integer number of
+// seconds. Durations which aren't a whole number of seconds are rejected.
+func SecondsConverter(value interface{}) (interface{}, error) {
+	return convertDuration(value, time.Second, "seconds")
+}
+
+func convertDuration(value interface{}, unit time.Duration, name string) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return nil, err
+	}
+
+	if duration%unit != 0 {
+		return nil, fmt.Errorf("duration '%s' is not a whole number of %s", str, name)
+	}
+
+	return int64(duration / unit), nil
+}
+
+// FileConverter converts a file path string value into the content of the
+// file.
+func FileConverter(value interface{}) (interface{}, error) {
+	if str, ok := value.(string); ok {
+		body, err := os.ReadFile(str)
+		return string(body), err
+	}
+	return value, nil
+}
+
 func init() {
 	RegisterConverter(time.Duration(0), ConverterFn(DurationConverter))
+	RegisterConverter(ByteSize(0), ConverterFn(ByteSizeConverter))
+	RegisterConverter(Rate(0), ConverterFn(RateConverter))
+	RegisterConverter(Percent(0), ConverterFn(PercentConverter))
+
+	RegisterNamedConverter("millis", ConverterFn(MillisConverter))
+	RegisterNamedConverter("seconds", ConverterFn(SecondsConverter))
+	RegisterNamedConverter("file", ConverterFn(FileConverter))
 }
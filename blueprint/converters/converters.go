@@ -0,0 +1,270 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+// Package converters registers blueprint converters for commonly used types of
+// the go standard library. The package is meant to be imported for its side
+// effects:
+//
+//	import _ "github.com/RAttab/goblueprint/blueprint/converters"
+//
+// All the converters expect string values and leave other values as is. The
+// base64 converter for []byte values is registered as a named converter and
+// must be selected through the conv struct tag option.
+package converters
+
+import (
+	"github.com/RAttab/goblueprint/blueprint"
+
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TimeLayouts lists the layouts attempted in order by TimeConverter.
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// TimeConverter converts string values into time.Time values using the first
+// matching layout in TimeLayouts.
+func TimeConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	for _, layout := range TimeLayouts {
+		if t, err := time.Parse(layout, str); err == nil {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to parse time '%s'; expected one of the layouts: %s",
+		str, strings.Join(TimeLayouts, ", "))
+}
+
+// LocationConverter converts string values into *time.Location values.
+func LocationConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	loc, err := time.LoadLocation(str)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time location '%s': %s", str, err)
+	}
+	return loc, nil
+}
+
+// IPConverter converts string values into net.IP values.
+func IPConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	if ip := net.ParseIP(str); ip != nil {
+		return ip, nil
+	}
+	return nil, fmt.Errorf("invalid IP address '%s'", str)
+}
+
+// IPNetConverter converts string values in the CIDR notation (eg.
+// 10.0.0.0/8) into *net.IPNet values.
+func IPNetConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR network '%s'", str)
+	}
+	return ipNet, nil
+}
+
+// TCPAddrConverter converts string values of the form ip:port into
+// *net.TCPAddr values. Hostnames are rejected as they would require a DNS
+// lookup while loading.
+func TCPAddrConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	ip, port, zone, err := parseAddr(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TCP address '%s': %s", str, err)
+	}
+	return &net.TCPAddr{IP: ip, Port: port, Zone: zone}, nil
+}
+
+// UDPAddrConverter converts string values of the form ip:port into
+// *net.UDPAddr values. Hostnames are rejected as they would require a DNS
+// lookup while loading.
+func UDPAddrConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	ip, port, zone, err := parseAddr(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UDP address '%s': %s", str, err)
+	}
+	return &net.UDPAddr{IP: ip, Port: port, Zone: zone}, nil
+}
+
+// parseAddr splits an address of the form ip:port without resolving it. An
+// empty ip (eg. ":80") is left nil.
+func parseAddr(str string) (ip net.IP, port int, zone string, err error) {
+	host, portStr, err := net.SplitHostPort(str)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	number, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("invalid port '%s'", portStr)
+	}
+
+	if host == "" {
+		return nil, int(number), "", nil
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("'%s' is not an IP address; hostnames aren't resolved", host)
+	}
+
+	return net.IP(addr.AsSlice()), int(number), addr.Zone(), nil
+}
+
+// URLConverter converts string values into *url.URL values.
+func URLConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	u, err := url.Parse(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL '%s': %s", str, err)
+	}
+	return u, nil
+}
+
+// RegexpConverter compiles string values into *regexp.Regexp values.
+func RegexpConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	re, err := regexp.Compile(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regexp '%s': %s", str, err)
+	}
+	return re, nil
+}
+
+// FileModeConverter converts string values in octal notation (eg. "0644") into
+// os.FileMode values.
+func FileModeConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	mode, err := strconv.ParseUint(str, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid octal file mode '%s'", str)
+	}
+	return os.FileMode(mode), nil
+}
+
+// TemplateConverter parses string values into *text/template.Template values.
+func TemplateConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	tmpl, err := template.New("blueprint").Parse(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %s", err)
+	}
+	return tmpl, nil
+}
+
+// BytesConverter decodes base64 encoded string values into []byte values. It's
+// registered as the base64 named converter so that it only applies to the
+// fields which select it (eg. `blueprint:"conv=base64"`).
+func BytesConverter(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	bytes, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 value '%s': %s", str, err)
+	}
+	return bytes, nil
+}
+
+func deref(conv blueprint.ConverterFn) blueprint.ConverterFn {
+	return func(value interface{}) (interface{}, error) {
+		value, err := conv(value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch obj := value.(type) {
+		case *net.TCPAddr:
+			return *obj, nil
+		case *net.UDPAddr:
+			return *obj, nil
+		case *url.URL:
+			return *obj, nil
+		}
+		return value, nil
+	}
+}
+
+func init() {
+	blueprint.RegisterConverter(time.Time{}, blueprint.ConverterFn(TimeConverter))
+	blueprint.RegisterConverter((*time.Location)(nil), blueprint.ConverterFn(LocationConverter))
+
+	blueprint.RegisterConverter(net.IP{}, blueprint.ConverterFn(IPConverter))
+	blueprint.RegisterConverter((*net.IPNet)(nil), blueprint.ConverterFn(IPNetConverter))
+
+	blueprint.RegisterConverter((*net.TCPAddr)(nil), blueprint.ConverterFn(TCPAddrConverter))
+	blueprint.RegisterConverter(net.TCPAddr{}, deref(TCPAddrConverter))
+	blueprint.RegisterConverter((*net.UDPAddr)(nil), blueprint.ConverterFn(UDPAddrConverter))
+	blueprint.RegisterConverter(net.UDPAddr{}, deref(UDPAddrConverter))
+
+	blueprint.RegisterConverter((*url.URL)(nil), blueprint.ConverterFn(URLConverter))
+	blueprint.RegisterConverter(url.URL{}, deref(URLConverter))
+
+	blueprint.RegisterConverter((*regexp.Regexp)(nil), blueprint.ConverterFn(RegexpConverter))
+	blueprint.RegisterConverter(os.FileMode(0), blueprint.ConverterFn(FileModeConverter))
+	blueprint.RegisterConverter((*template.Template)(nil), blueprint.ConverterFn(TemplateConverter))
+
+	blueprint.RegisterNamedConverter("base64", blueprint.ConverterFn(BytesConverter))
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package converters
+
+import (
+	"github.com/RAttab/goblueprint/blueprint"
+
+	"bytes"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+type Config struct {
+	Time     time.Time
+	Date     time.Time
+	Location *time.Location
+	IP       net.IP
+	Net      *net.IPNet
+	TCP      *net.TCPAddr
+	UDP      net.UDPAddr
+	URL      *url.URL
+	Regexp   *regexp.Regexp
+	Mode     os.FileMode
+	Template *template.Template
+	Bytes    []byte `blueprint:"conv=base64"`
+}
+
+func TestConverters(t *testing.T) {
+	config := &Config{}
+
+	err := blueprint.LoadJSONInto([]byte(`{
+        "Time": "2014-06-01T12:30:00Z",
+        "Date": "2014-06-01",
+        "Location": "UTC",
+        "IP": "10.1.2.3",
+        "Net": "10.0.0.0/8",
+        "TCP": "[fe80::1%eth0]:80",
+        "UDP": "127.0.0.1:53",
+        "URL": "http://example.com/blah?a=b",
+        "Regexp": "^[a-z]+$",
+        "Mode": "0644",
+        "Template": "Hello {{.}}",
+        "Bytes": "YmxhaA=="
+    }`), config)
+
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	if exp := time.Date(2014, 6, 1, 12, 30, 0, 0, time.UTC); !config.Time.Equal(exp) {
+		t.Errorf("FAIL: time %s != %s", config.Time, exp)
+	}
+	if exp := time.Date(2014, 6, 1, 0, 0, 0, 0, time.UTC); !config.Date.Equal(exp) {
+		t.Errorf("FAIL: date %s != %s", config.Date, exp)
+	}
+	if config.Location != time.UTC {
+		t.Errorf("FAIL: location %s != UTC", config.Location)
+	}
+	if !config.IP.Equal(net.ParseIP("10.1.2.3")) {
+		t.Errorf("FAIL: ip %s != 10.1.2.3", config.IP)
+	}
+	if config.Net.String() != "10.0.0.0/8" {
+		t.Errorf("FAIL: net %s != 10.0.0.0/8", config.Net)
+	}
+	if config.TCP.String() != "[fe80::1%eth0]:80" {
+		t.Errorf("FAIL: tcp %s != [fe80::1%%eth0]:80", config.TCP)
+	}
+	if config.UDP.String() != "127.0.0.1:53" {
+		t.Errorf("FAIL: udp %s != 127.0.0.1:53", &config.UDP)
+	}
+	if config.URL.Host != "example.com" || config.URL.Query().Get("a") != "b" {
+		t.Errorf("FAIL: url %s != http://example.com/blah?a=b", config.URL)
+	}
+	if !config.Regexp.MatchString("blah") || config.Regexp.MatchString("BLAH") {
+		t.Errorf("FAIL: regexp %s doesn't match as expected", config.Regexp)
+	}
+	if config.Mode != 0644 {
+		t.Errorf("FAIL: mode %s != 0644", config.Mode)
+	}
+	if !bytes.Equal(config.Bytes, []byte("blah")) {
+		t.Errorf("FAIL: bytes %q != blah", config.Bytes)
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := config.Template.Execute(buffer, "World"); err != nil || buffer.String() != "Hello World" {
+		t.Errorf("FAIL: template output '%s' != 'Hello World' (%v)", buffer, err)
+	}
+}
+
+func TestConverters_Errors(t *testing.T) {
+	CheckError(t, `{ "Time": "yesterday" }`, "unable to parse time 'yesterday'")
+	CheckError(t, `{ "IP": "10.1.2" }`, "invalid IP address '10.1.2' at 'IP'")
+	CheckError(t, `{ "Net": "10.0.0.0" }`, "invalid CIDR network")
+	CheckError(t, `{ "Regexp": "[a-" }`, "invalid regexp")
+	CheckError(t, `{ "Mode": "0999" }`, "invalid octal file mode")
+	CheckError(t, `{ "Bytes": "!!" }`, "invalid base64 value")
+	CheckError(t, `{ "TCP": "example.com:80" }`, "hostnames aren't resolved")
+	CheckError(t, `{ "TCP": "127.0.0.1:http" }`, "invalid port 'http'")
+	CheckError(t, `{ "UDP": "127.0.0.1" }`, "invalid UDP address")
+}
+
+func CheckError(t *testing.T, json, exp string) {
+	if err := blueprint.LoadJSONInto([]byte(json), &Config{}); err == nil {
+		t.Errorf("FAIL: expected error for %s", json)
+
+	} else if !strings.Contains(err.Error(), exp) {
+		t.Errorf("FAIL: error '%s' doesn't contain '%s'", err, exp)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type Timeouts struct {
+	Read    int    `blueprint:"conv=millis"`
+	Write   int32  `blueprint:"conv=seconds"`
+	Message string `blueprint:"conv=file"`
+	Body    []byte `blueprint:"conv=file"`
+}
+
+type BadConv struct {
+	Value int `blueprint:"conv=milis"`
+}
+
+func init() {
+	Register(Timeouts{})
+	Register(BadConv{})
+}
+
+func TestConverters_Named(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "message")
+	if err := os.WriteFile(file, []byte("blah"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	CheckLoadJSON(t, `{
+        "a!Timeouts": {
+            "Read": "1.5s",
+            "Write": "2m",
+            "Message": "`+file+`",
+            "Body": "`+file+`"
+        },
+        "b!Timeouts": { "Read": 250 }
+    }`, map[string]interface{}{
+		"a": &Timeouts{Read: 1500, Write: 120, Message: "blah", Body: []byte("blah")},
+		"b": &Timeouts{Read: 250},
+	})
+
+	CheckLoadJSONError(t, `{ "a!BadConv": { "Value": "1s" } }`, "unknown converter 'milis'; did you mean 'millis'?")
+	CheckLoadJSONError(t, `{ "a!Timeouts": { "Message": "/does/not/exist" } }`, "no such file")
+	CheckLoadJSONError(t, `{ "a!Timeouts": { "Read": "1500us" } }`, "duration '1500us' is not a whole number of milliseconds")
+	CheckLoadJSONError(t, `{ "a!Timeouts": { "Write": "999ms" } }`, "duration '999ms' is not a whole number of seconds")
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/goklog/klog"
+
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// EnumConverter converts string values into the named constants of an integer
+// backed enum type. If Flags is set then multiple names can be combined using
+// the '|' character (eg. "Read|Write") and their values are or-ed together.
+type EnumConverter struct {
+	Type  reflect.Type
+	Names map[string]int64
+	Flags bool
+}
+
+// Convert converts the given string value into a value of the enum type. Non
+// string values are returned as is.
+func (enum *EnumConverter) Convert(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	names := []string{str}
+	if enum.Flags {
+		names = strings.Split(str, "|")
+	}
+
+	var result int64
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if enum.Flags && name == "" {
+			continue
+		}
+
+		if value, ok := enum.Names[name]; ok {
+			result |= value
+		} else {
+			return nil, fmt.Errorf("unknown value '%s' for '%s'; expected one of: %s",
+				name, enum.Type, strings.Join(enum.names(), ", "))
+		}
+	}
+
+	obj := reflect.New(enum.Type).Elem()
+
+	switch enum.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if obj.OverflowInt(result) {
+			return nil, fmt.Errorf("value '%d' of '%s' overflows '%s'", result, str, enum.Type)
+		}
+		obj.SetInt(result)
+
+	default:
+		if result < 0 || obj.OverflowUint(uint64(result)) {
+			return nil, fmt.Errorf("value '%d' of '%s' overflows '%s'", result, str, enum.Type)
+		}
+		obj.SetUint(uint64(result))
+	}
+
+	return obj.Interface(), nil
+}
+
+func (enum *EnumConverter) names() []string {
+	var names []string
+	for name := range enum.Names {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// RegisterEnum registers a converter for the type of the given object which
+// maps the given names to their constant values.
+func RegisterEnum(obj interface{}, names map[string]int64) {
+	registerEnum(obj, names, false)
+}
+
+// RegisterFlags registers a converter for the type of the given object which
+// maps the given names to their bit-flag values. Multiple names can be combined
+// using the '|' character.
+func RegisterFlags(obj interface{}, names map[string]int64) {
+	registerEnum(obj, names, true)
+}
+
+func registerEnum(obj interface{}, names map[string]int64, flags bool) {
+	typ := reflect.TypeOf(obj)
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		klog.KPanicf("blueprint.enums.error", "enum type '%s' must be integer backed", typ)
+	}
+
+	RegisterConverter(obj, &EnumConverter{Type: typ, Names: names, Flags: flags})
+}
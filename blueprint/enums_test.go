@@ -0,0 +1,85 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warning
+)
+
+type Mode uint8
+
+const (
+	Read Mode = 1 << iota
+	Write
+	Exec
+)
+
+type Logger struct {
+	Level Level
+	Mode  Mode
+}
+
+func init() {
+	Register(Logger{})
+
+	RegisterEnum(Level(0), map[string]int64{
+		"Debug":   int64(Debug),
+		"Info":    int64(Info),
+		"Warning": int64(Warning),
+	})
+
+	RegisterFlags(Mode(0), map[string]int64{
+		"Read":  int64(Read),
+		"Write": int64(Write),
+		"Exec":  int64(Exec),
+	})
+}
+
+func TestEnums(t *testing.T) {
+	CheckLoadJSON(t, `{
+        "a!Logger": { "Level": "Warning", "Mode": "Read|Write" },
+        "b!Logger": { "Level": 1, "Mode": "Exec" }
+    }`, map[string]interface{}{
+		"a": &Logger{Level: Warning, Mode: Read | Write},
+		"b": &Logger{Level: Info, Mode: Exec},
+	})
+
+	CheckLoadJSONError(t, `{ "a!Logger": { "Level": "Warn" } }`, "expected one of: Debug, Info, Warning")
+	CheckLoadJSONError(t, `{ "a!Logger": { "Mode": "Read|Exe" } }`, "unknown value 'Exe'")
+}
+
+type Small int8
+
+type Tiny uint8
+
+func TestEnums_Overflow(t *testing.T) {
+	small := &EnumConverter{Type: reflect.TypeOf(Small(0)), Names: map[string]int64{"Big": 300, "Ok": 1}}
+	tiny := &EnumConverter{Type: reflect.TypeOf(Tiny(0)), Names: map[string]int64{"Neg": -1, "A": 1, "B": 256}, Flags: true}
+
+	if value, err := small.Convert("Ok"); err != nil || value != Small(1) {
+		t.Errorf("FAIL: unexpected conversion: %v, %v", value, err)
+	}
+
+	for _, test := range []struct {
+		conv  *EnumConverter
+		value string
+	}{
+		{small, "Big"},
+		{tiny, "Neg"},
+		{tiny, "A|B"},
+	} {
+		if _, err := test.conv.Convert(test.value); err == nil || !strings.Contains(err.Error(), "overflows") {
+			t.Errorf("FAIL(%s): expected overflow error got %v", test.value, err)
+		}
+	}
+}
@@ -3,7 +3,11 @@
 package blueprint
 
 import (
+	"github.com/RAttab/gopath/path"
+
 	"bytes"
+	"errors"
+	"fmt"
 )
 
 // Errors aggregates multiple errors encountered during loading and reports them
@@ -22,3 +26,33 @@ func (errors Errors) Error() string {
 
 	return buffer.String()
 }
+
+// ErrNilRegistration is returned when attempting to register a nil value.
+var ErrNilRegistration = errors.New("attempted to register nil")
+
+// DuplicateError is returned when attempting to register an entity under a
+// name or type which is already registered.
+type DuplicateError struct {
+	Kind string
+	Name string
+}
+
+// Error returns a string representation of the error.
+func (err *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate %s registration attempt for '%s'", err.Kind, err.Name)
+}
+
+// PathError is an error reported by the loader for a given path of the
+// blueprint.
+type PathError struct {
+	Err  error
+	Path path.P
+}
+
+// Error returns a string representation of the error.
+func (err *PathError) Error() string {
+	return fmt.Sprintf("%s at '%s'", err.Err, err.Path)
+}
+
+// Unwrap returns the underlying error.
+func (err *PathError) Unwrap() error { return err.Err }
@@ -80,7 +80,11 @@ func ExampleBlueprint() {
 
 	// We can then access our constructed multi object and execute it to get the
 	// expected result. Magic!
-	multi := values["multi"].(*MultiHandler)
+	multi, err := blueprint.LookupAs[*MultiHandler](values, "multi")
+	if err != nil {
+		panic(err)
+	}
+
 	multi.Handle()
 
 	// Output:
@@ -0,0 +1,142 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/goklog/klog"
+
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Functions indexes named functions which can be assigned by the loader to
+// fields of function type (eg. hash functions, comparators, middlewares, etc.)
+// by specifying the name of the function as a string value.
+type Functions struct {
+	mutex sync.Mutex
+	funcs map[string]reflect.Value
+}
+
+// Register associates the given function with the given name. Invalid or
+// duplicate registrations are fatal; use TryRegister to handle them.
+func (fns *Functions) Register(name string, fn interface{}) {
+	if err := fns.TryRegister(name, fn); err != nil {
+		klog.KFatalf("blueprint.functions.error", "%s", err)
+	}
+}
+
+// TryRegister associates the given function with the given name. Returns
+// ErrNilRegistration if the function is nil, a DuplicateError if a function
+// is already associated with the name or an error if fn isn't a function.
+func (fns *Functions) TryRegister(name string, fn interface{}) error {
+	value := reflect.ValueOf(fn)
+	if !value.IsValid() || (value.Kind() == reflect.Func && value.IsNil()) {
+		return ErrNilRegistration
+	}
+
+	if value.Kind() != reflect.Func {
+		return fmt.Errorf("attempted to register non-function '%T' as '%s'", fn, name)
+	}
+
+	fns.mutex.Lock()
+	defer fns.mutex.Unlock()
+
+	if fns.funcs == nil {
+		fns.funcs = make(map[string]reflect.Value)
+	}
+
+	if _, ok := fns.funcs[name]; ok {
+		return &DuplicateError{Kind: "function", Name: name}
+	}
+
+	fns.funcs[name] = value
+	return nil
+}
+
+// Get returns the function associated with the given name or false as the
+// second parameter if no functions exists for that name.
+func (fns *Functions) Get(name string) (interface{}, bool) {
+	fns.mutex.Lock()
+
+	fn, ok := fns.funcs[name]
+
+	fns.mutex.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+	return fn.Interface(), true
+}
+
+// Names returns the sorted names of all the registered functions.
+func (fns *Functions) Names() []string {
+	fns.mutex.Lock()
+
+	var names []string
+	for name := range fns.funcs {
+		names = append(names, name)
+	}
+
+	fns.mutex.Unlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// String returns the string representation of the registered functions
+// suitable for debugging.
+func (fns *Functions) String() string {
+	buffer := new(bytes.Buffer)
+	buffer.WriteString("[")
+
+	for _, name := range fns.Names() {
+		fn, _ := fns.Get(name)
+		buffer.WriteString("\n    ")
+		buffer.WriteString(name)
+		buffer.WriteString(": ")
+		buffer.WriteString(reflect.TypeOf(fn).String())
+	}
+
+	buffer.WriteString("\n]")
+	return buffer.String()
+}
+
+// convert returns the function associated with the given name if its
+// signature is compatible with the given function type.
+func (fns *Functions) convert(typ reflect.Type, name string) (interface{}, error) {
+	fns.mutex.Lock()
+
+	fn, ok := fns.funcs[name]
+
+	fns.mutex.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s'%s", name, suggest(name, fns.Names()))
+	}
+
+	if !fn.Type().AssignableTo(typ) {
+		return nil, fmt.Errorf("function '%s' of type '%s' is not assignable to '%s'", name, fn.Type(), typ)
+	}
+
+	return fn.Convert(typ).Interface(), nil
+}
+
+// DefaultFunctions is a global Functions object used by the loader when
+// assigning to fields of function type.
+var DefaultFunctions Functions
+
+// RegisterFunc associates the given function with the given name.
+func RegisterFunc(name string, fn interface{}) { DefaultFunctions.Register(name, fn) }
+
+// TryRegisterFunc associates the given function with the given name or returns
+// an error if the function can't be registered.
+func TryRegisterFunc(name string, fn interface{}) error {
+	return DefaultFunctions.TryRegister(name, fn)
+}
+
+// GetFunc returns the function associated with the given name or false as the
+// second parameter if no functions exists for that name.
+func GetFunc(name string) (interface{}, bool) { return DefaultFunctions.Get(name) }
@@ -0,0 +1,39 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"testing"
+)
+
+type HashFn func(string) uint32
+
+type Hasher struct {
+	Hash HashFn
+	Less func(a, b int) bool
+}
+
+func init() {
+	Register(Hasher{})
+
+	RegisterFunc("len", func(s string) uint32 { return uint32(len(s)) })
+	RegisterFunc("less", func(a, b int) bool { return a < b })
+}
+
+func TestFunctions(t *testing.T) {
+	values, err := LoadJSON([]byte(`{ "h!Hasher": { "Hash": "len", "Less": "less" } }`))
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	hasher := values["h"].(*Hasher)
+	if hash := hasher.Hash("blah"); hash != 4 {
+		t.Errorf("FAIL: unexpected hash %d != 4", hash)
+	}
+	if !hasher.Less(1, 2) {
+		t.Errorf("FAIL: unexpected less result")
+	}
+
+	CheckLoadJSONError(t, `{ "h!Hasher": { "Hash": "lenn" } }`, "did you mean 'len'?")
+	CheckLoadJSONError(t, `{ "h!Hasher": { "Hash": "less" } }`, "is not assignable")
+}
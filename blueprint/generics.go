@@ -0,0 +1,60 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/goklog/klog"
+	"github.com/RAttab/gopath/path"
+
+	"fmt"
+	"reflect"
+)
+
+// typeOf returns the type of T which, unlike reflect.TypeOf, also works for
+// interface types.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// RegisterType registers the type T in the default registry. Pointer types are
+// registered as their element type.
+func RegisterType[T any]() {
+	typ := typeOf[T]()
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if err := DefaultRegistry.tryRegisterType(typ); err != nil {
+		klog.KFatalf("blueprint.registry.error", "%s", err)
+	}
+}
+
+// LoadJSONAs loads the given JSON blueprint into a new object of type T.
+func LoadJSONAs[T any](body []byte) (T, error) {
+	var value T
+	err := LoadJSONInto(body, &value)
+	return value, err
+}
+
+// LookupAs returns the value at the given dot separated path of the given
+// values (eg. as returned by LoadJSON) as a T. An error is returned if the
+// path doesn't exist or if its value is not a T.
+func LookupAs[T any](values interface{}, target string) (T, error) {
+	var result T
+
+	value, err := path.New(target).Get(values)
+	if err != nil {
+		return result, fmt.Errorf("%s at '%s'", err, target)
+	}
+
+	if value == nil {
+		return result, fmt.Errorf("missing value at '%s'", target)
+	}
+
+	result, ok := value.(T)
+	if !ok {
+		return result, fmt.Errorf("expected '%s' got '%T' at '%s'", typeOf[T](), value, target)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"testing"
+)
+
+type Generic struct{ I int }
+
+func TestGenerics(t *testing.T) {
+	RegisterType[*Generic]()
+	defer Unregister(Generic{})
+
+	if typ, ok := Get("Generic"); !ok || typ != typeOf[Generic]() {
+		t.Errorf("FAIL: Generic not registered: %v", typ)
+	}
+
+	nested, err := LoadJSONAs[map[string]Struct](
+		[]byte(`{ "a": { "I": 1, "S": "x", "Base!Impl": { "I": 2 } } }`))
+
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json: %s", err)
+	}
+
+	if nested["a"].I != 1 || !nested["a"].Base.Eq(&Impl{I: 2}) {
+		t.Errorf("FAIL: unexpected value: %+v", nested)
+	}
+
+	values, err := LoadJSON([]byte(`{ "a!Generic": { "I": 1 }, "b!Generic": {} }`))
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json: %s", err)
+	}
+
+	if value, err := LookupAs[*Generic](values, "a"); err != nil || value.I != 1 {
+		t.Errorf("FAIL: unexpected lookup result: %v, %v", value, err)
+	}
+
+	if _, err := LookupAs[*Generic](values, "b"); err != nil {
+		t.Errorf("FAIL: unexpected error for empty object: %s", err)
+	}
+
+	if _, err := LookupAs[*Struct](values, "a"); err == nil || err.Error() != "expected '*blueprint.Struct' got '*blueprint.Generic' at 'a'" {
+		t.Errorf("FAIL: unexpected error for type mismatch: %v", err)
+	}
+
+	if _, err := LookupAs[*Generic](values, "z"); err == nil || err.Error() != "missing value at 'z'" {
+		t.Errorf("FAIL: unexpected error for missing value: %v", err)
+	}
+}
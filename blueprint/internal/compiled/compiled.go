@@ -0,0 +1,83 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+// Package compiled verifies the code generated by blueprint.Compile against
+// the output of blueprint.LoadJSON for the same blueprint. The generated code
+// lives in build_gen.go and is refreshed with go test -update.
+package compiled
+
+import (
+	"github.com/RAttab/goblueprint/blueprint"
+
+	"time"
+)
+
+// Handler is implemented by the objects linked in the blueprint.
+type Handler interface {
+	Handle() string
+}
+
+// Printer is a leaf handler.
+type Printer struct {
+	Value   string
+	Timeout time.Duration
+	Started time.Time
+	Size    blueprint.ByteSize
+}
+
+// Handle implements the Handler interface.
+func (printer *Printer) Handle() string { return printer.Value }
+
+// Multi aggregates handlers.
+type Multi struct {
+	Handlers []Handler
+	Primary  Handler
+	Weights  map[string]float64
+	Tags     []string
+	Fallback *Printer
+
+	count int
+}
+
+// Handle implements the Handler interface.
+func (multi *Multi) Handle() (result string) {
+	for _, handler := range multi.Handlers {
+		result += handler.Handle()
+	}
+	return
+}
+
+// Node forms cycles through its links.
+type Node struct {
+	Name string
+	Next *Node
+}
+
+func init() {
+	blueprint.Register(Printer{})
+	blueprint.Register(Multi{})
+	blueprint.Register(Node{})
+}
+
+// Blueprint is compiled into build_gen.go.
+const Blueprint = `{
+    "hello!Printer": {
+        "Value": "Hello ",
+        "Timeout": "5s",
+        "Started": "2014-01-02T03:04:05Z",
+        "Size": "1KB"
+    },
+    "world!Printer": { "Value": "World!" },
+    "multi!Multi": {
+        "#Handlers": [ "hello", "world" ],
+        "#Primary": "hello",
+        "Weights": { "a": 0.5, "b": 2 },
+        "Tags": [ "x", "y" ],
+        "Fallback": { "Value": "?" }
+    },
+    "a!Node": { "Name": "a", "#Next": "b" },
+    "b!Node": { "Name": "b", "#Next": "a" },
+    "#tags": "multi.Tags",
+    "#weights": "multi.Weights",
+    "n": 42,
+    "s": "str"
+}`
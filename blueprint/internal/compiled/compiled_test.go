@@ -0,0 +1,76 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package compiled
+
+import (
+	"github.com/RAttab/goblueprint/blueprint"
+
+	"bytes"
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate build_gen.go")
+
+func TestCompile(t *testing.T) {
+	values, err := blueprint.LoadJSON([]byte(Blueprint))
+	if err != nil {
+		t.Fatalf("FAIL: unable to load blueprint\n%v", err)
+	}
+
+	body, err := blueprint.Compile(values, blueprint.CompileOptions{
+		Package:     "compiled",
+		PackagePath: "github.com/RAttab/goblueprint/blueprint/internal/compiled",
+	})
+
+	if err != nil {
+		t.Fatalf("FAIL: unable to compile blueprint\n%v", err)
+	}
+
+	if *update {
+		if err := os.WriteFile("build_gen.go", body, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	if old, err := os.ReadFile("build_gen.go"); err != nil || !bytes.Equal(old, body) {
+		t.Errorf("FAIL: build_gen.go is stale; run go test -update\n%s", body)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	exp, err := blueprint.LoadJSON([]byte(Blueprint))
+	if err != nil {
+		t.Fatalf("FAIL: unable to load blueprint\n%v", err)
+	}
+
+	values := Build()
+
+	if !reflect.DeepEqual(values, exp) {
+		t.Errorf("FAIL: %v != %v", values, exp)
+	}
+
+	multi := values["multi"].(*Multi)
+	if multi.Primary != multi.Handlers[0] || multi.Handlers[0] != values["hello"] {
+		t.Errorf("FAIL: links are not shared")
+	}
+
+	if tags := values["tags"].([]string); &tags[0] != &multi.Tags[0] {
+		t.Errorf("FAIL: linked slices are not shared")
+	}
+
+	if weights := values["weights"].(map[string]float64); reflect.ValueOf(weights).Pointer() != reflect.ValueOf(multi.Weights).Pointer() {
+		t.Errorf("FAIL: linked maps are not shared")
+	}
+
+	if a := values["a"].(*Node); a.Next.Next != a || a.Next != values["b"] {
+		t.Errorf("FAIL: cycle is not preserved")
+	}
+
+	if result := multi.Handle(); result != "Hello World!" {
+		t.Errorf("FAIL: unexpected result '%s'", result)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"github.com/RAttab/goklog/klog"
 	"github.com/RAttab/gopath/path"
 
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // MaxLinksDepth is used to protect against cyclic links by limitting how far a
@@ -26,42 +28,357 @@ type Loader struct {
 	// Values is the value to be constructed.
 	Values interface{}
 
-	links  map[string]path.P
-	errors Errors
+	// Registry is used to lookup types and named converters. DefaultRegistry is
+	// used if nil.
+	Registry *Registry
+
+	// Coerce enables the parsing of string values into booleans, numbers,
+	// slices and maps when they can't otherwise be assigned. This is meant for
+	// sources that can only provide strings like environment variables or
+	// command line flags.
+	Coerce bool
+
+	// Strict rejects keys that don't match an exported field of the struct
+	// they're applied to. When Values is a map, Finish also reports the
+	// top-level objects and links which are never linked or, if Roots isn't
+	// empty, which aren't reachable from the keys listed in Roots.
+	Strict bool
+
+	// JSONTags enables the name of the json struct tag as an alternative name
+	// for struct fields. The name of the blueprint struct tag is always
+	// accepted (eg. `blueprint:"max_body"`).
+	JSONTags bool
+
+	// Match determines how path components are matched against the names of
+	// struct fields. Exact matches are always attempted first.
+	Match MatchPolicy
+
+	// Roots lists the top-level keys which are used directly by the caller and
+	// should therefore never be reported as unused in strict mode.
+	Roots []string
+
+	links    map[string]path.P
+	provided map[string]interface{}
+	imports  map[string]string
+	calls    []call
+	errors   Errors
+	warnings Errors
+	defaults bool
 }
 
-// Add sets the object at the given path to value.
+// Add sets the object at the given path to value. Values which can't be
+// assigned directly are first converted to the type of the object at the given
+// path. Numbers of type json.Number and values of fields with a conv struct tag
+// option are always converted.
+//
+// If the last component of the path doesn't refer to an exported field but
+// matches a setter method of the struct (eg. SetTimeout for Timeout) then the
+// method is called with the converted value instead. Errors returned by the
+// setter are reported at the given path.
 func (loader *Loader) Add(src path.P, value interface{}) {
 	klog.KPrintf("blueprint.loader.add.debug", "src=%s, value={%T, %v}", src, value, value)
 
-	err := src.Set(loader.Values, value)
-	if err == nil {
-		return
+	loader.ErrorAt(loader.add(loader.translate(src), value), src)
+}
+
+func (loader *Loader) add(dst path.P, value interface{}) error {
+	if err := loader.checkStrict(dst); err != nil {
+		return err
+	}
+
+	loader.instantiate(dst)
+
+	if !loader.defaults {
+		loader.warnDeprecatedField(dst)
+	}
+
+	if ok, err := loader.callSetter(dst, value); ok {
+		return err
+	}
+
+	err := path.ErrInvalidType
+	if !loader.mustConvert(dst, value) {
+		err = dst.Set(loader.Values, value)
 	}
 
 	if err == path.ErrInvalidType {
-		var typ reflect.Type
-		if typ, err = src.Type(loader.Values); err == nil {
-			if value, err = convert(typ, value); err == nil {
-				err = src.Set(loader.Values, value)
+		err = loader.addConverted(dst, value)
+	}
+
+	return err
+}
+
+func (loader *Loader) checkStrict(dst path.P) error {
+	if !loader.Strict {
+		return nil
+	}
+	return loader.checkKeys(dst)
+}
+
+func (loader *Loader) mustConvert(src path.P, value interface{}) bool {
+	if _, ok := value.(json.Number); ok {
+		return true
+	}
+
+	if field, ok := loader.field(src); ok {
+		_, ok = parseTag(field)["conv"]
+		return ok
+	}
+
+	return false
+}
+
+func (loader *Loader) addConverted(src path.P, value interface{}) error {
+	typ, err := src.Type(loader.Values)
+	if err != nil {
+		return err
+	}
+
+	if value, err = loader.convert(src, typ, value); err != nil {
+		return err
+	}
+
+	return src.Set(loader.Values, value)
+}
+
+// convert converts the value to be added at the given path using the struct
+// tag options of the field at that path if any and then using the registered
+// converters. Strings left as is are then coerced if enabled.
+func (loader *Loader) convert(src path.P, typ reflect.Type, value interface{}) (interface{}, error) {
+	var err error
+
+	if field, ok := loader.field(src); ok {
+		tag := parseTag(field)
+
+		if name, ok := tag["conv"]; ok {
+			if value, err = loader.convertNamed(name, typ, value); err != nil {
+				return nil, err
 			}
+
+		} else if unit, ok := tag["unit"]; ok {
+			return convertUnit(unit, typ, value)
+		}
+	}
+
+	if value, err = convert(typ, value); err != nil {
+		return nil, err
+	}
+
+	if str, ok := value.(string); ok && loader.Coerce {
+		return coerce(typ, str)
+	}
+
+	return value, nil
+}
+
+// convertNamed converts the given value using the named converter and casts
+// the result to the given type if they're of compatible kinds.
+func (loader *Loader) convertNamed(name string, typ reflect.Type, value interface{}) (interface{}, error) {
+	reg := loader.registry()
+
+	conv, ok := reg.NamedConverter(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown converter '%s'%s", name, suggest(name, reg.NamedConverters()))
+	}
+
+	value, err := conv.Convert(value)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := reflect.ValueOf(value)
+	if !obj.IsValid() || obj.Type() == typ {
+		return value, nil
+	}
+
+	if isNumeric(obj.Type()) && isNumeric(typ) {
+		return convertNumber(typ, json.Number(fmt.Sprint(value)))
+	}
+
+	if obj.Kind() == reflect.String && obj.Type().ConvertibleTo(typ) {
+		return obj.Convert(typ).Interface(), nil
+	}
+
+	return value, nil
+}
+
+func (loader *Loader) registry() *Registry {
+	if loader.Registry != nil {
+		return loader.Registry
+	}
+	return &DefaultRegistry
+}
+
+// field returns the struct field at the given path or false if the object at
+// the given path is not the field of a struct.
+func (loader *Loader) field(src path.P) (reflect.StructField, bool) {
+	if len(src) == 0 {
+		return reflect.StructField{}, false
+	}
+
+	parent := src[:len(src)-1]
+
+	typ, err := parent.Type(loader.Values)
+	if err != nil {
+		return reflect.StructField{}, false
+	}
+
+	if typ.Kind() == reflect.Interface {
+		if value, err := parent.Get(loader.Values); err == nil && value != nil {
+			typ = reflect.TypeOf(value)
 		}
 	}
 
-	loader.ErrorAt(err, src)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+
+	return typ.FieldByName(src[len(src)-1])
 }
 
 // Type asserts the type of an object at the given path.  This is useful when
 // dealing with interfaces which can't be pathed through unless they're
 // associated with a concrete type.
+//
+// Newly instantiated objects, whether through Type or when pathing through a
+// nil pointer to a struct or a missing struct element of a slice or map, are
+// initialized using the default option of their fields' blueprint struct tag
+// (eg. `blueprint:"default=30s"`). The unset fields of the struct given to
+// LoadJSONInto are initialized in the same way. Defaults are converted in the
+// same way as values given to Add with coercion enabled.
 func (loader *Loader) Type(src path.P, name string) {
 	klog.KPrintf("blueprint.loader.type.debug", "src=%s, name=%s", src, name)
 
-	if value, ok := New(name); !ok {
-		loader.ErrorAt(fmt.Errorf("unknown type '%s'", name), src)
+	loader.ErrorAt(loader.setType(loader.translate(src), name), src)
+}
 
-	} else if err := src.Set(loader.Values, value); err != nil {
-		loader.ErrorAt(err, src)
+func (loader *Loader) setType(dst path.P, name string) error {
+	if err := loader.checkStrict(dst); err != nil {
+		return err
+	}
+
+	loader.instantiate(dst)
+
+	reg := loader.registry()
+
+	typ, err := reg.Lookup(loader.qualify(name))
+	if err != nil {
+		return err
+	}
+
+	value := reflect.New(typ).Interface()
+
+	if typ, err := dst.Type(loader.Values); err == nil && typ.Kind() == reflect.Interface {
+		if !reflect.TypeOf(value).Implements(typ) {
+			return fmt.Errorf("%s does not implement %s; candidates: %s",
+				name, typ, strings.Join(reg.Implementers(typ), ", "))
+		}
+	}
+
+	if err := dst.Set(loader.Values, value); err != nil {
+		return err
+	}
+
+	loader.warnDeprecatedField(dst)
+	loader.warnDeprecatedType(dst, name, reflect.TypeOf(value))
+	loader.applyDefaults(dst, reflect.TypeOf(value))
+	return nil
+}
+
+// instantiate creates the nil pointers to structs along with the missing struct
+// elements of slices and maps along the given path and applies their defaults.
+func (loader *Loader) instantiate(src path.P) {
+	for i := 1; i < len(src); i++ {
+		current := src[:i:i]
+
+		typ, err := current.Type(loader.Values)
+		if err != nil {
+			return
+		}
+
+		if typ.Kind() == reflect.Struct {
+			loader.instantiateElem(current, typ)
+			continue
+		}
+
+		if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		if value, err := current.Get(loader.Values); err != nil {
+			return
+
+		} else if value != nil && !reflect.ValueOf(value).IsNil() {
+			continue
+		}
+
+		if err := current.Set(loader.Values, reflect.New(typ.Elem()).Interface()); err != nil {
+			return
+		}
+
+		loader.applyDefaults(current, typ)
+	}
+}
+
+// instantiateElem creates the struct element of a slice or map at the given
+// path if it doesn't exist yet and applies its defaults.
+func (loader *Loader) instantiateElem(src path.P, typ reflect.Type) {
+	parent, err := src[:len(src)-1].Type(loader.Values)
+	if err != nil {
+		return
+	}
+
+	for parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+
+	if parent.Kind() != reflect.Slice && parent.Kind() != reflect.Map {
+		return
+	}
+
+	if value, err := src.Get(loader.Values); err == nil && value != nil {
+		return
+	}
+
+	if err := src.Set(loader.Values, reflect.New(typ).Elem().Interface()); err != nil {
+		return
+	}
+
+	loader.applyDefaults(src, typ)
+}
+
+// applyRootDefaults applies the defaults of Values if it's a pointer to a
+// struct. Fields which are already set are left untouched.
+func (loader *Loader) applyRootDefaults() {
+	if typ := reflect.TypeOf(loader.Values); typ != nil && typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct {
+		loader.applyDefaults(nil, typ)
+	}
+}
+
+// applyDefaults adds the default values of the fields of the struct at the
+// given path including the fields of nested structs. Fields which are already
+// set are left untouched.
+func (loader *Loader) applyDefaults(src path.P, typ reflect.Type) {
+	coerce, defaults := loader.Coerce, loader.defaults
+	loader.Coerce, loader.defaults = true, true
+	defer func() { loader.Coerce, loader.defaults = coerce, defaults }()
+
+	for _, field := range Fields(typ) {
+		current := append(src[:len(src):len(src)], field.Name)
+
+		if value, ok := field.Default(); ok {
+			if old, err := current.Get(loader.Values); err == nil && old != nil && !reflect.ValueOf(old).IsZero() {
+				continue
+			}
+			loader.Add(current, value)
+
+		} else if field.Type.Kind() == reflect.Struct {
+			loader.applyDefaults(current, field.Type)
+		}
 	}
 }
 
@@ -78,18 +395,95 @@ func (loader *Loader) Link(src, target path.P) {
 	loader.links[src.String()] = target
 }
 
+// Provide makes the given value available to links under the given name. A
+// provided value is referenced by prefixing its name with the '@' character
+// (eg. @db or @db.Conn) and is never added to Values which means that it's not
+// serialized along with the constructed object nor owned by the loader.
+func (loader *Loader) Provide(name string, value interface{}) {
+	klog.KPrintf("blueprint.loader.provide.debug", "name=%s, value={%T, %v}", name, value, value)
+
+	if loader.provided == nil {
+		loader.provided = make(map[string]interface{})
+	}
+
+	if _, ok := loader.provided[name]; ok {
+		loader.ErrorAt(fmt.Errorf("duplicate provided value '%s'", name), path.P{"@" + name})
+		return
+	}
+
+	loader.provided[name] = value
+}
+
+// Import associates the given prefix with the given package path such that the
+// type name prefix.Name given to Type refers to the type package/Name (eg.
+// with the import h for github.com/acme/handlers, h.Printer refers to
+// github.com/acme/handlers/Printer). Prefixes can't be empty or contain the '.'
+// or '/' characters as they would make type names ambiguous.
+func (loader *Loader) Import(prefix, pkg string) {
+	klog.KPrintf("blueprint.loader.import.debug", "prefix=%s, pkg=%s", prefix, pkg)
+
+	if prefix == "" || strings.ContainsAny(prefix, "./") {
+		loader.ErrorAt(fmt.Errorf("invalid import prefix '%s'", prefix), path.P{"@import", prefix})
+		return
+	}
+
+	if loader.imports == nil {
+		loader.imports = make(map[string]string)
+	}
+
+	if old, ok := loader.imports[prefix]; ok && old != pkg {
+		loader.ErrorAt(fmt.Errorf("conflicting imports '%s' and '%s'", old, pkg), path.P{"@import", prefix})
+		return
+	}
+
+	loader.imports[prefix] = pkg
+}
+
+// qualify expands the import prefix of the given type name if any.
+func (loader *Loader) qualify(name string) string {
+	if i := strings.Index(name, "."); i > 0 {
+		if pkg, ok := loader.imports[name[:i]]; ok {
+			return pkg + "/" + name[i+1:]
+		}
+	}
+	return name
+}
+
 // ErrorAt is used to report an error while loading the given path. Errors are
-// accumulated during loading and only reported back to the user when Finish is
-// called.
+// accumulated during loading as PathError and only reported back to the user
+// when Finish is called.
 func (loader *Loader) ErrorAt(err error, src path.P) {
 	if err != nil {
-		loader.errors = append(loader.errors, fmt.Errorf("%s at '%s'", err, src))
+		loader.errors = append(loader.errors, &PathError{Err: err, Path: append(path.P{}, src...)})
+	}
+}
+
+// WarnAt is used to report a non-fatal issue (eg. the use of a deprecated type)
+// while loading the given path. Warnings are logged and accumulated but are
+// never returned as errors by Finish.
+func (loader *Loader) WarnAt(err error, src path.P) {
+	if err != nil {
+		err = &PathError{Err: err, Path: append(path.P{}, src...)}
+		klog.KPrintf("blueprint.loader.warning", "%s", err)
+		loader.warnings = append(loader.warnings, err)
 	}
 }
 
+// Warnings returns all the warnings reported so far.
+func (loader *Loader) Warnings() Errors { return loader.warnings }
+
 // Finish completes and returns the object. If errors were encountered during
 // loading, they're all returned here as type Errors.
+//
+// Once all the links are resolved, the constraints specified in the blueprint
+// struct tag of every field reachable from Values are checked (eg.
+// `blueprint:"required,min=1,max=64,pattern=^[a-z]+$,oneof=a|b"`) and all
+// violations are reported as errors. The calls added through Call are executed
+// in between so that they can affect the checked values. Objects reachable
+// through the pointers of values given to Provide are left unchecked.
 func (loader *Loader) Finish() (interface{}, error) {
+	loader.translateLinks()
+
 	if loader.links != nil {
 		for src, target := range loader.links {
 			var value interface{}
@@ -99,12 +493,17 @@ func (loader *Loader) Finish() (interface{}, error) {
 			klog.KPrintf("blueprint.loader.finish.debug", "src=%s, target=%s", src, dst)
 
 			if err == nil {
-				if value, err = dst.Get(loader.Values); err == nil && value == nil {
+				if value, err = loader.get(dst); err == nil && value == nil {
 					err = fmt.Errorf("unable to link '%s' to nil value '%s'", src, dst)
 				}
 			}
 
 			if err == nil {
+				err = loader.checkStrict(path.New(src))
+			}
+
+			if err == nil {
+				loader.instantiate(path.New(src))
 				err = path.New(src).Set(loader.Values, value)
 			}
 
@@ -114,6 +513,28 @@ func (loader *Loader) Finish() (interface{}, error) {
 		}
 	}
 
+	for _, call := range loader.sortedCalls() {
+		loader.ErrorAt(loader.call(call), join(call.src, call.method))
+	}
+
+	if loader.Strict {
+		loader.checkUnused()
+	}
+
+	// Values provided by the caller aren't owned by the loader so the pointers
+	// reachable from them are marked as seen before validating.
+	walker := &walker{fn: func(path.P, Field, reflect.Value) {}, seen: make(map[visit]bool)}
+	for _, value := range loader.provided {
+		walker.walk(nil, reflect.ValueOf(value))
+	}
+
+	walker.fn = func(src path.P, field Field, value reflect.Value) {
+		for _, err := range validate(field, value) {
+			loader.ErrorAt(err, src)
+		}
+	}
+	walker.walk(nil, reflect.ValueOf(loader.Values))
+
 	// Required otherwise we set the type param on the error interface which
 	// makes the error non-nil. One of those fun parts of the go language.
 	if loader.errors != nil {
@@ -122,11 +543,26 @@ func (loader *Loader) Finish() (interface{}, error) {
 	return loader.Values, nil
 }
 
+func (loader *Loader) get(target path.P) (interface{}, error) {
+	if len(target) == 0 || !strings.HasPrefix(target[0], "@") {
+		return target.Get(loader.Values)
+	}
+
+	value, ok := loader.provided[target[0][1:]]
+	if !ok {
+		return nil, fmt.Errorf("unknown provided value '%s'", target[0])
+	}
+
+	return target[1:].Get(value)
+}
+
 func (loader *Loader) resolve(target path.P, depth int) (path.P, error) {
 	if depth > MaxLinksDepth {
 		return nil, fmt.Errorf("reached max links depth for '%s'", target)
 	}
 
+	target = loader.translate(target)
+
 	for i := 1; i <= len(target); i++ {
 		if redirect, ok := loader.links[target[:i].String()]; ok {
 			return loader.resolve(append(redirect, target[i:]...), depth+1)
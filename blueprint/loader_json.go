@@ -5,8 +5,10 @@ package blueprint
 import (
 	"github.com/RAttab/gopath/path"
 
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -33,7 +35,28 @@ import (
 //
 // A link causes the object to be filled with the object at the specified
 // path. Optionally, an array can also be filled in from multiple paths as
-// demonstrated by the bar key.
+// demonstrated by the bar key. Keys which start with a literal '#' character
+// are escaped by doubling it (eg. "##foo" is the key "#foo").
+//
+// Links can also target values provided by the caller through Loader.Provide by
+// prefixing the name of the provided value with the '@' character. eg.
+//
+//     { "#DB": "@db" }
+//
+// Package paths can be abbreviated in type names by declaring imports under the
+// special "@import" key at the top-level. eg.
+//
+//     {
+//         "@import": { "h": "github.com/acme/handlers" },
+//         "printer!h.Printer": { ... }
+//     }
+//
+// Methods can be called on an object once all the links are resolved by listing
+// the calls under the special "@call" key of the object. Each call maps the
+// name of the method to its array of arguments where string arguments prefixed
+// by the '#' character are links (use "##" to escape a literal '#'). eg.
+//
+//     { "mux!ServeMux": { "@call": [ { "Handle": [ "/", "#handler" ] } ] } }
 //
 // This JSON format has one major downside: it's not possible to qualify the
 // type of array elements. This becomes an issue when dealing with an array of
@@ -57,19 +80,39 @@ func LoadJSONInto(body []byte, value interface{}) error {
 	return err
 }
 
+// LoadJSON constructs the loader's Values using the JSON representation
+// described in LoadJSON. This is useful when the loader must be configured
+// before loading (eg. through Provide).
+func (loader *Loader) LoadJSON(body []byte) (interface{}, error) {
+	return (&loaderJSON{Loader: loader}).Load(body)
+}
+
 type loaderJSON struct{ *Loader }
 
 func (loader *loaderJSON) Load(body []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
 	var obj interface{}
-	if err := json.Unmarshal(body, &obj); err != nil {
+	if err := decoder.Decode(&obj); err != nil {
 		return nil, err
 	}
 
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level JSON value")
+	}
+
+	loader.applyRootDefaults()
 	loader.load(nil, obj)
 	return loader.Finish()
 }
 
 func (loader *loaderJSON) load(current path.P, obj interface{}) {
+	if loader.isLeaf(current) {
+		loader.Add(current, obj)
+		return
+	}
+
 	switch obj.(type) {
 
 	case map[string]interface{}:
@@ -83,9 +126,43 @@ func (loader *loaderJSON) load(current path.P, obj interface{}) {
 	}
 }
 
+// isLeaf returns true if the object at the given path implements
+// json.Unmarshaler or is associated with a setter in which case the JSON
+// subtree is handed to it as a whole instead of being pathed into.
+func (loader *loaderJSON) isLeaf(current path.P) bool {
+	if len(current) == 0 {
+		return false
+	}
+
+	dst := loader.translate(current)
+
+	if _, ok := loader.setterAt(dst); ok {
+		return true
+	}
+
+	typ, err := dst.Type(loader.Values)
+	return err == nil && implements(typ, jsonUnmarshalerType)
+}
+
 func (loader *loaderJSON) loadMap(current path.P, obj map[string]interface{}) {
+	if imports, ok := obj["@import"]; ok {
+		loader.loadImports(current, imports)
+	}
+
 	for key, value := range obj {
-		if strings.HasPrefix(key, "#") {
+		if key == "@import" {
+			continue
+		}
+
+		if key == "@call" {
+			loader.loadCalls(current, value)
+			continue
+		}
+
+		if strings.HasPrefix(key, "##") {
+			key = key[1:]
+
+		} else if strings.HasPrefix(key, "#") {
 			loader.loadLinks(append(current, key[1:]), value)
 			continue
 		}
@@ -116,6 +193,62 @@ func (loader *loaderJSON) loadLinks(current path.P, obj interface{}) {
 	}
 }
 
+func (loader *loaderJSON) loadImports(current path.P, obj interface{}) {
+	if len(current) > 0 {
+		loader.ErrorAt(fmt.Errorf("imports are only allowed at the top-level"), append(current, "@import"))
+		return
+	}
+
+	imports, ok := obj.(map[string]interface{})
+	if !ok {
+		loader.ErrorAt(fmt.Errorf("expected object of imports got '%T'", obj), path.P{"@import"})
+		return
+	}
+
+	for prefix, value := range imports {
+		if pkg, ok := value.(string); ok {
+			loader.Import(prefix, pkg)
+		} else {
+			loader.ErrorAt(fmt.Errorf("expected package path got '%T'", value), path.P{"@import", prefix})
+		}
+	}
+}
+
+func (loader *loaderJSON) loadCalls(current path.P, obj interface{}) {
+	calls, ok := obj.([]interface{})
+	if !ok {
+		loader.ErrorAt(fmt.Errorf("expected array of calls got '%T'", obj), append(current, "@call"))
+		return
+	}
+
+	for i, item := range calls {
+		call, ok := item.(map[string]interface{})
+		if !ok || len(call) != 1 {
+			loader.ErrorAt(fmt.Errorf("expected object with a single method got '%v'", item),
+				append(current, "@call", strconv.Itoa(i)))
+			continue
+		}
+
+		for method, value := range call {
+			args, ok := value.([]interface{})
+			if !ok {
+				loader.ErrorAt(fmt.Errorf("expected array of arguments got '%T'", value), append(current, method))
+				continue
+			}
+
+			for j, arg := range args {
+				if str, ok := arg.(string); ok && strings.HasPrefix(str, "##") {
+					args[j] = str[1:]
+				} else if ok && strings.HasPrefix(str, "#") {
+					args[j] = path.New(str[1:])
+				}
+			}
+
+			loader.Call(current, method, args...)
+		}
+	}
+}
+
 func (loader *loaderJSON) loadSlice(current path.P, obj []interface{}) {
 	for i, item := range obj {
 		loader.load(append(current, strconv.Itoa(i)), item)
@@ -3,12 +3,18 @@
 package blueprint
 
 import (
+	"strings"
 	"testing"
 )
 
 type Blah struct{ A []string }
 
-func init() { Register(Blah{}) }
+type Escaped struct{ Tags map[string]string }
+
+func init() {
+	Register(Blah{})
+	Register(Escaped{})
+}
 
 func TestLoader_JSON(t *testing.T) {
 	json := `{
@@ -83,3 +89,61 @@ func CheckLoadJSON(t *testing.T, json string, exp map[string]interface{}) {
 
 	CheckValues(t, values, exp)
 }
+
+func TestLoader_JSONEscape(t *testing.T) {
+	CheckLoadJSON(t, `{
+        "##a": "a",
+        "###b": "b",
+        "e!Escaped": { "Tags": { "##c": "c", "d": "d" } },
+        "#link": "e.Tags.#c"
+    }`, map[string]interface{}{
+		"#a":   "a",
+		"##b":  "b",
+		"e":    &Escaped{Tags: map[string]string{"#c": "c", "d": "d"}},
+		"link": "c",
+	})
+}
+
+func TestLoader_JSONProvide(t *testing.T) {
+	json := `{
+        "X!Struct": {
+            "#I": "@impl.I",
+            "#Base": "@impl"
+        },
+        "#Y": "@impl.S"
+    }`
+
+	impl := &Impl{I: 10, S: "blah"}
+
+	loader := NewLoader()
+	loader.Provide("impl", impl)
+
+	values, err := loader.LoadJSON([]byte(json))
+	if err != nil {
+		t.Errorf("FAIL: unable to load json\n%v", err)
+		return
+	}
+
+	CheckValues(t, values.(map[string]interface{}), map[string]interface{}{
+		"X": &Struct{I: 10, Base: impl},
+		"Y": "blah",
+	})
+
+	if x := values.(map[string]interface{})["X"].(*Struct); x.Base != impl {
+		t.Errorf("FAIL: provided value was copied: %p != %p", x.Base, impl)
+	}
+
+	loader = NewLoader()
+	if _, err := loader.LoadJSON([]byte(`{ "#X": "@unknown" }`)); err == nil {
+		t.Errorf("FAIL: expected error for unknown provided value")
+	}
+}
+
+func CheckLoadJSONError(t *testing.T, json, exp string) {
+	if _, err := LoadJSON([]byte(json)); err == nil {
+		t.Errorf("FAIL: expected error for %s", json)
+
+	} else if !strings.Contains(err.Error(), exp) {
+		t.Errorf("FAIL: error '%s' doesn't contain '%s'", err, exp)
+	}
+}
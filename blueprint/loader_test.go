@@ -5,6 +5,7 @@ package blueprint
 import (
 	"github.com/RAttab/gopath/path"
 
+	"errors"
 	"fmt"
 	"github.com/RAttab/goset"
 	"reflect"
@@ -181,3 +182,65 @@ func CheckValues(t *testing.T, values, exp map[string]interface{}) {
 		}
 	}
 }
+
+type NotBase struct{ I int }
+
+type AnonBase struct {
+	Base interface{ Eq(Base) bool }
+}
+
+func init() {
+	Register(NotBase{})
+	Register(AnonBase{})
+}
+
+func TestLoader_Implementers(t *testing.T) {
+	exp := []string{"Impl", "Struct"}
+	if names := Implementers(reflect.TypeOf((*Base)(nil)).Elem()); !reflect.DeepEqual(names, exp) {
+		t.Errorf("FAIL: implementers %v != %v", names, exp)
+	}
+
+	loader := NewLoader()
+	loader.TestType(t, "X", "Struct")
+
+	if loader.Type(path.New("X.Base"), "NotBase"); len(loader.errors) != 1 {
+		t.Fatalf("FAIL: expected error for incompatible type")
+	}
+
+	if err, exp := loader.errors[0].Error(), "NotBase does not implement blueprint.Base; candidates: Impl, Struct at 'X.Base'"; err != exp {
+		t.Errorf("FAIL: error '%s' != '%s'", err, exp)
+	}
+
+	loader = NewLoader()
+	loader.TestType(t, "X", "AnonBase")
+
+	if loader.Type(path.New("X.Base"), "NotBase"); len(loader.errors) != 1 {
+		t.Fatalf("FAIL: expected error for incompatible type")
+	}
+
+	if err, exp := loader.errors[0].Error(), "NotBase does not implement interface { Eq(blueprint.Base) bool }; candidates: Impl, Struct at 'X.Base'"; err != exp {
+		t.Errorf("FAIL: error '%s' != '%s'", err, exp)
+	}
+
+	if names := Implementers(reflect.TypeOf(Impl{})); names != nil {
+		t.Errorf("FAIL: unexpected implementers for non-interface: %v", names)
+	}
+}
+
+func TestLoader_PathError(t *testing.T) {
+	errSentinel := errors.New("sentinel")
+
+	loader := NewLoader()
+	loader.ErrorAt(errSentinel, path.New("a.b"))
+
+	_, err := loader.Finish()
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("FAIL: unexpected errors: %v", err)
+	}
+
+	var pathErr *PathError
+	if !errors.Is(errs[0], errSentinel) || !errors.As(errs[0], &pathErr) || pathErr.Path.String() != "a.b" {
+		t.Errorf("FAIL: unable to unwrap '%s'", errs[0])
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/goklog/klog"
+	"github.com/RAttab/gopath/path"
+
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Meta holds the descriptive metadata associated with a registered type which
+// is available to documentation generators through Registry.Meta.
+type Meta struct {
+
+	// Description is a human readable description of the type.
+	Description string
+
+	// Categories is used to group related types.
+	Categories []string
+
+	// Version is the version of the type's configuration format.
+	Version string
+
+	// Deprecated is a deprecation notice which, if non-empty, causes the loader
+	// to emit a warning whenever the type is used.
+	Deprecated string
+
+	// Replacement is the name of the type that should be used instead of a
+	// deprecated type.
+	Replacement string
+}
+
+// String returns a single line representation of the metadata.
+func (meta Meta) String() string {
+	buffer := new(bytes.Buffer)
+
+	if meta.Version != "" {
+		fmt.Fprintf(buffer, " (%s)", meta.Version)
+	}
+
+	if len(meta.Categories) > 0 {
+		fmt.Fprintf(buffer, " [%s]", strings.Join(meta.Categories, ", "))
+	}
+
+	if meta.Description != "" {
+		fmt.Fprintf(buffer, " - %s", meta.Description)
+	}
+
+	if notice := meta.deprecation(); notice != "" {
+		fmt.Fprintf(buffer, " (deprecated: %s)", notice)
+	}
+
+	return strings.TrimSpace(buffer.String())
+}
+
+// deprecation returns the deprecation notice along with the replacement or an
+// empty string if the type isn't deprecated.
+func (meta Meta) deprecation() string {
+	if meta.Deprecated == "" && meta.Replacement == "" {
+		return ""
+	}
+
+	notice := meta.Deprecated
+	if meta.Replacement != "" {
+		if notice != "" {
+			notice += "; "
+		}
+		notice += fmt.Sprintf("use '%s' instead", meta.Replacement)
+	}
+
+	return notice
+}
+
+// RegisterMeta registers the given value's type in the same way as Register
+// and associates the given metadata with the type. Duplicate registrations are
+// fatal; use TryRegisterMeta to handle them.
+func (reg *Registry) RegisterMeta(value interface{}, meta Meta) {
+	if value == nil {
+		klog.KPanicf("blueprint.registry.error", "attempted to register nil")
+	}
+
+	if err := reg.TryRegisterMeta(value, meta); err != nil {
+		klog.KFatalf("blueprint.registry.error", "%s", err)
+	}
+}
+
+// TryRegisterMeta registers the given value's type in the same way as
+// TryRegister and associates the given metadata with the type. Returns
+// ErrNilRegistration if the value is nil or a DuplicateError if the type is
+// already registered.
+func (reg *Registry) TryRegisterMeta(value interface{}, meta Meta) error {
+	if err := reg.TryRegister(value); err != nil {
+		return err
+	}
+
+	typ := indirectType(value)
+
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	if reg.metas == nil {
+		reg.metas = make(map[reflect.Type]Meta)
+	}
+
+	reg.metas[typ] = meta
+	return nil
+}
+
+// Meta returns the metadata associated with the given type or false as the
+// second parameter if no metadata was registered for the type.
+func (reg *Registry) Meta(typ reflect.Type) (Meta, bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	reg.mutex.Lock()
+
+	meta, ok := reg.metas[typ]
+
+	reg.mutex.Unlock()
+
+	return meta, ok
+}
+
+// RegisterMeta registers the given value's type and associates the given
+// metadata with the type.
+func RegisterMeta(value interface{}, meta Meta) { DefaultRegistry.RegisterMeta(value, meta) }
+
+// TryRegisterMeta registers the given value's type and associates the given
+// metadata with the type or returns an error if the type can't be registered.
+func TryRegisterMeta(value interface{}, meta Meta) error {
+	return DefaultRegistry.TryRegisterMeta(value, meta)
+}
+
+// warnDeprecatedField emits a warning if the field at the given path is marked as
+// deprecated through the deprecated option of its blueprint struct tag.
+func (loader *Loader) warnDeprecatedField(dst path.P) {
+	field, ok := loader.field(dst)
+	if !ok {
+		return
+	}
+
+	if notice, ok := parseTag(field)["deprecated"]; ok && notice != "" {
+		loader.WarnAt(fmt.Errorf("deprecated field '%s': %s", field.Name, notice), dst)
+	} else if ok {
+		loader.WarnAt(fmt.Errorf("deprecated field '%s'", field.Name), dst)
+	}
+}
+
+// warnDeprecatedType emits a warning if the given type is marked as deprecated
+// in the registry.
+func (loader *Loader) warnDeprecatedType(dst path.P, name string, typ reflect.Type) {
+	if meta, ok := loader.registry().Meta(typ); ok {
+		if notice := meta.deprecation(); notice != "" {
+			loader.WarnAt(fmt.Errorf("deprecated type '%s': %s", name, notice), dst)
+		}
+	}
+}
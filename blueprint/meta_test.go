@@ -0,0 +1,81 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type OldPrinter struct {
+	Value  string
+	Prefix string `blueprint:"deprecated=use Value instead"`
+}
+
+func TestMeta(t *testing.T) {
+	reg := &Registry{}
+	reg.RegisterMeta(OldPrinter{}, Meta{
+		Description: "prints things",
+		Categories:  []string{"output", "debug"},
+		Version:     "v1",
+		Deprecated:  "printing is obsolete",
+		Replacement: "Printer",
+	})
+
+	meta, ok := reg.Meta(reflect.TypeOf(&OldPrinter{}))
+	if !ok || meta.Description != "prints things" {
+		t.Errorf("FAIL: unexpected meta %+v", meta)
+	}
+
+	exp := "OldPrinter (v1) [output, debug] - prints things (deprecated: printing is obsolete; use 'Printer' instead)"
+	if str := reg.String(); !strings.Contains(str, exp) {
+		t.Errorf("FAIL: registry string doesn't contain '%s':\n%s", exp, str)
+	}
+
+	loader := &Loader{Values: make(map[string]interface{}), Registry: reg}
+	if _, err := loader.LoadJSON([]byte(`{ "p!OldPrinter": { "Value": "a", "Prefix": "b" } }`)); err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	warnings := loader.Warnings()
+	if len(warnings) != 2 {
+		t.Fatalf("FAIL: expected 2 warnings got %d\n%s", len(warnings), warnings)
+	}
+
+	for _, exp := range []string{
+		"deprecated type 'OldPrinter': printing is obsolete; use 'Printer' instead at 'p'",
+		"deprecated field 'Prefix': use Value instead at 'p.Prefix'",
+	} {
+		if !strings.Contains(warnings.Error(), exp) {
+			t.Errorf("FAIL: missing warning '%s' in:\n%s", exp, warnings)
+		}
+	}
+}
+
+type OldDefaults struct {
+	Retries int `blueprint:"default=3,deprecated=use Attempts instead"`
+}
+
+func TestMeta_DeprecatedDefaults(t *testing.T) {
+	reg := &Registry{}
+	reg.Register(OldDefaults{})
+
+	loader := &Loader{Values: make(map[string]interface{}), Registry: reg}
+	if _, err := loader.LoadJSON([]byte(`{ "a!OldDefaults": {} }`)); err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	if warnings := loader.Warnings(); len(warnings) != 0 {
+		t.Errorf("FAIL: unexpected warnings for defaults:\n%s", warnings)
+	}
+
+	loader = &Loader{Values: make(map[string]interface{}), Registry: reg}
+	if _, err := loader.LoadJSON([]byte(`{ "a!OldDefaults": { "Retries": 5 } }`)); err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	if warnings := loader.Warnings(); len(warnings) != 1 {
+		t.Errorf("FAIL: expected 1 warning got %d\n%s", len(warnings), warnings)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/gopath/path"
+
+	"reflect"
+	"strings"
+)
+
+// MatchPolicy determines how the components of a path are matched against the
+// names of struct fields.
+type MatchPolicy int
+
+const (
+	// MatchExact requires path components to match the field names exactly.
+	MatchExact MatchPolicy = iota
+
+	// MatchFold matches path components against field names regardless of
+	// case (eg. maxbody matches MaxBody).
+	MatchFold
+
+	// MatchSnake matches path components against field names while ignoring
+	// case and the '_' and '-' separators (eg. max_body matches MaxBody).
+	MatchSnake
+)
+
+// Match returns true if the given path component matches the given field name
+// according to the policy.
+func (policy MatchPolicy) Match(key, name string) bool {
+	switch policy {
+	case MatchFold:
+		return strings.EqualFold(key, name)
+	case MatchSnake:
+		return strings.EqualFold(stripSeparators(key), stripSeparators(name))
+	}
+	return key == name
+}
+
+func stripSeparators(str string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return r
+	}, str)
+}
+
+// names returns the names accepted for the given field indexed by decreasing
+// order of precedence: the go name, the name of the blueprint struct tag and,
+// optionally, the name of the json struct tag. Missing names are left empty.
+func (loader *Loader) names(field reflect.StructField) []string {
+	names := []string{field.Name, parseTag(field)["name"], ""}
+
+	if loader.JSONTags {
+		if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "-" {
+			names[2] = name
+		}
+	}
+
+	return names
+}
+
+// fieldName returns the go name of the field of the given struct type which
+// matches the given key. All the fields are checked for a given kind of name
+// before moving on to the next one so that a go name always has precedence
+// over a tag name of another field.
+func (loader *Loader) fieldName(typ reflect.Type, key string) (string, bool) {
+	policies := []MatchPolicy{MatchExact}
+	if loader.Match != MatchExact {
+		policies = append(policies, loader.Match)
+	}
+
+	var fields []reflect.StructField
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.PkgPath == "" {
+			fields = append(fields, field)
+		}
+	}
+
+	for _, policy := range policies {
+		for kind := 0; kind < 3; kind++ {
+			for _, field := range fields {
+				if name := loader.names(field)[kind]; name != "" && policy.Match(key, name) {
+					return field.Name, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// translate replaces the components of the given path which refer to struct
+// fields by the go name of these fields. Components which can't be typed yet
+// (eg. nil interfaces) are left as is.
+func (loader *Loader) translate(src path.P) path.P {
+	result := make(path.P, 0, len(src))
+
+	for _, component := range src {
+		if typ, ok := loader.typeAt(result); ok && typ.Kind() == reflect.Struct {
+			if name, ok := loader.fieldName(typ, component); ok {
+				component = name
+			}
+		}
+
+		result = append(result, component)
+	}
+
+	return result
+}
+
+// translateLinks translates the source paths of all the links. This can only
+// be done once all the objects are created which is why it's delayed until
+// Finish.
+func (loader *Loader) translateLinks() {
+	if loader.links == nil {
+		return
+	}
+
+	links := make(map[string]path.P)
+	for src, target := range loader.links {
+		links[loader.translate(path.New(src)).String()] = target
+	}
+
+	loader.links = links
+}
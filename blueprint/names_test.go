@@ -0,0 +1,76 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"testing"
+)
+
+type Naming struct {
+	MaxBody   int    `blueprint:"max_body"`
+	UserAgent string `json:"user_agent,omitempty"`
+	KeepAlive bool   `blueprint:"name=keep-alive"`
+	Next      *Naming
+}
+
+type NamingConflict struct {
+	Alias  string `json:"Name"`
+	Name   string
+	First  string `json:"second"`
+	Second string `blueprint:"name=second"`
+}
+
+func init() {
+	Register(Naming{})
+	Register(NamingConflict{})
+}
+
+func TestNames(t *testing.T) {
+	CheckLoadJSON(t, `{
+        "a!Naming": { "max_body": 10, "UserAgent": "blah", "keep-alive": true },
+        "#b": "a.max_body"
+    }`, map[string]interface{}{
+		"a": &Naming{MaxBody: 10, UserAgent: "blah", KeepAlive: true},
+		"b": int(10),
+	})
+
+	CheckLoadJSONError(t, `{ "a!Naming": { "user_agent": "blah" } }`, "at 'a.user_agent'")
+
+	CheckNames(t, &Loader{Values: make(map[string]interface{}), JSONTags: true}, `{
+        "a!Naming": { "user_agent": "blah", "keep-alive": true }
+    }`, map[string]interface{}{
+		"a": &Naming{UserAgent: "blah", KeepAlive: true},
+	})
+
+	CheckNames(t, &Loader{Values: make(map[string]interface{}), Match: MatchFold}, `{
+        "a!Naming": { "maxbody": 10, "USERAGENT": "blah", "keepalive": true, "next": { "MAX_BODY": 20 } }
+    }`, map[string]interface{}{
+		"a": &Naming{MaxBody: 10, UserAgent: "blah", KeepAlive: true, Next: &Naming{MaxBody: 20}},
+	})
+
+	CheckNames(t, &Loader{Values: make(map[string]interface{}), Match: MatchSnake}, `{
+        "a!Naming": { "max_body": 10, "user_agent": "blah", "keep_alive": true, "next": { "keep_alive": true } },
+        "#b": "a.next.keep_alive"
+    }`, map[string]interface{}{
+		"a": &Naming{MaxBody: 10, UserAgent: "blah", KeepAlive: true, Next: &Naming{KeepAlive: true}},
+		"b": true,
+	})
+}
+
+func TestNames_Precedence(t *testing.T) {
+	CheckNames(t, &Loader{Values: make(map[string]interface{}), JSONTags: true}, `{
+        "a!NamingConflict": { "Name": "name", "second": "second" }
+    }`, map[string]interface{}{
+		"a": &NamingConflict{Name: "name", Second: "second"},
+	})
+}
+
+func CheckNames(t *testing.T, loader *Loader, json string, exp map[string]interface{}) {
+	values, err := loader.LoadJSON([]byte(json))
+	if err != nil {
+		t.Errorf("FAIL: unable to load json\n%v", err)
+		return
+	}
+
+	CheckValues(t, values.(map[string]interface{}), exp)
+}
@@ -6,8 +6,10 @@ import (
 	"github.com/RAttab/goklog/klog"
 
 	"bytes"
+	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 )
 
@@ -15,61 +17,326 @@ import (
 // objects as needed.
 //
 // Types can either be accessed by their short form (eg. MyType) or their fully
-// qualified form (eg. github.com/me/golib/MyType). Conflicts with the short
-// form are resolved arbitrarily. The fully qualified is therefore recommended
-// when conflicts are a possibility.
+// qualified form (eg. github.com/me/golib/MyType). Short forms shared by
+// multiple types are ambiguous and can only be accessed through their fully
+// qualified form or an alias. Aliases can also be used to keep old names
+// working when a type is renamed or moved.
 type Registry struct {
-	mutex sync.Mutex
-	types map[string]reflect.Type
+	mutex   sync.Mutex
+	types   map[string]reflect.Type
+	shorts  map[string][]reflect.Type
+	aliases map[string]reflect.Type
+	convs   map[string]Converter
+
+	setters map[reflect.Type]map[string]string
+	metas   map[reflect.Type]Meta
 }
 
 // Register associates the given value's type with the short and fully qualified
-// name.
+// name. Duplicate registrations are fatal; use TryRegister to handle them.
 func (reg *Registry) Register(value interface{}) {
 	if value == nil {
 		klog.KPanicf("blueprint.registry.error", "attempted to register nil")
 	}
 
-	typ := reflect.TypeOf(value)
-	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface {
-		typ = typ.Elem()
+	if err := reg.TryRegister(value); err != nil {
+		klog.KFatalf("blueprint.registry.error", "%s", err)
+	}
+}
+
+// TryRegister associates the given value's type with the short and fully
+// qualified name. Returns ErrNilRegistration if the value is nil or a
+// DuplicateError if the type is already registered.
+func (reg *Registry) TryRegister(value interface{}) error {
+	if value == nil {
+		return ErrNilRegistration
 	}
 
+	return reg.tryRegisterType(indirectType(value))
+}
+
+func (reg *Registry) tryRegisterType(typ reflect.Type) error {
 	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
 
 	if reg.types == nil {
 		reg.types = make(map[string]reflect.Type)
+		reg.shorts = make(map[string][]reflect.Type)
 	}
 
-	name := typ.Name()
-	if pkg := typ.PkgPath(); pkg != "" {
-		name = pkg + "/" + name
-	}
+	name := qualifiedName(typ)
 
 	if _, ok := reg.types[name]; ok {
-		klog.KFatalf("blueprint.registry.error", "duplicate registration attempt for '%s'", name)
+		return &DuplicateError{Kind: "type", Name: name}
 	}
 
 	reg.types[name] = typ
+	reg.shorts[typ.Name()] = append(reg.shorts[typ.Name()], typ)
 
-	// Add a shorthand alias to make life simpler.
-	if _, ok := reg.types[typ.Name()]; !ok {
-		reg.types[typ.Name()] = typ
+	return nil
+}
+
+// Unregister removes the given value's type from the registry along with its
+// aliases, setters and metadata. Returns false if the type wasn't registered.
+func (reg *Registry) Unregister(value interface{}) bool {
+	typ := indirectType(value)
+	if typ == nil {
+		return false
 	}
 
-	reg.mutex.Unlock()
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	name := qualifiedName(typ)
+
+	if reg.types[name] != typ {
+		return false
+	}
+
+	delete(reg.types, name)
+
+	var shorts []reflect.Type
+	for _, other := range reg.shorts[typ.Name()] {
+		if other != typ {
+			shorts = append(shorts, other)
+		}
+	}
+
+	if len(shorts) > 0 {
+		reg.shorts[typ.Name()] = shorts
+	} else {
+		delete(reg.shorts, typ.Name())
+	}
+
+	for alias, other := range reg.aliases {
+		if other == typ {
+			delete(reg.aliases, alias)
+		}
+	}
+
+	delete(reg.setters, typ)
+	delete(reg.metas, typ)
+
+	return true
 }
 
-// Get returns the type associated with the given name or false as the second
-// parameter if no types exists for that name.
-func (reg *Registry) Get(name string) (reflect.Type, bool) {
+// Clone returns a copy of the registry which can be modified without affecting
+// the original registry. Useful to build derived registries in tests.
+func (reg *Registry) Clone() *Registry {
 	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
 
-	typ, ok := reg.types[name]
+	clone := &Registry{}
 
-	reg.mutex.Unlock()
+	if reg.types != nil {
+		clone.types = make(map[string]reflect.Type)
+		for name, typ := range reg.types {
+			clone.types[name] = typ
+		}
+
+		clone.shorts = make(map[string][]reflect.Type)
+		for name, types := range reg.shorts {
+			clone.shorts[name] = append([]reflect.Type(nil), types...)
+		}
+	}
+
+	if reg.aliases != nil {
+		clone.aliases = make(map[string]reflect.Type)
+		for name, typ := range reg.aliases {
+			clone.aliases[name] = typ
+		}
+	}
+
+	if reg.convs != nil {
+		clone.convs = make(map[string]Converter)
+		for name, conv := range reg.convs {
+			clone.convs[name] = conv
+		}
+	}
+
+	if reg.setters != nil {
+		clone.setters = make(map[reflect.Type]map[string]string)
+		for typ, setters := range reg.setters {
+			clone.setters[typ] = make(map[string]string)
+			for key, method := range setters {
+				clone.setters[typ][key] = method
+			}
+		}
+	}
+
+	if reg.metas != nil {
+		clone.metas = make(map[reflect.Type]Meta)
+		for typ, meta := range reg.metas {
+			clone.metas[typ] = meta
+		}
+	}
+
+	return clone
+}
+
+// Alias associates the given name with the given value's type which must
+// already be registered. This is typically used to keep blueprints which
+// reference the old name of a renamed or moved type working. Invalid aliases
+// are fatal; use TryAlias to handle them.
+func (reg *Registry) Alias(name string, value interface{}) {
+	if err := reg.TryAlias(name, value); err != nil {
+		klog.KFatalf("blueprint.registry.error", "%s", err)
+	}
+}
+
+// TryAlias associates the given name with the given value's type which must
+// already be registered. Returns ErrNilRegistration if the value is nil, a
+// DuplicateError if the alias already exists or an error if the type isn't
+// registered.
+func (reg *Registry) TryAlias(name string, value interface{}) error {
+	typ := indirectType(value)
+	if typ == nil {
+		return ErrNilRegistration
+	}
+
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	if reg.types[qualifiedName(typ)] != typ {
+		return fmt.Errorf("alias '%s' refers to unregistered type '%s'", name, typ)
+	}
+
+	if reg.aliases == nil {
+		reg.aliases = make(map[string]reflect.Type)
+	}
+
+	if _, ok := reg.aliases[name]; ok {
+		return &DuplicateError{Kind: "alias", Name: name}
+	}
+
+	reg.aliases[name] = typ
+	return nil
+}
+
+// indirectType returns the type of the given value stripped of its pointers or
+// nil if the value is nil.
+func indirectType(value interface{}) reflect.Type {
+	typ := reflect.TypeOf(value)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface) {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// qualifiedName returns the fully qualified name of the given type (eg.
+// github.com/me/golib/MyType).
+func qualifiedName(typ reflect.Type) string {
+	if pkg := typ.PkgPath(); pkg != "" {
+		return pkg + "/" + typ.Name()
+	}
+	return typ.Name()
+}
+
+// names returns the preferred name of each registered type which is the short
+// form if it's unambiguous or the fully qualified form otherwise.
+func (reg *Registry) names() map[reflect.Type]string {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	names := make(map[reflect.Type]string)
+
+	for name, typ := range reg.types {
+		if len(reg.shorts[typ.Name()]) == 1 {
+			names[typ] = typ.Name()
+		} else {
+			names[typ] = name
+		}
+	}
+
+	return names
+}
+
+// Names returns the sorted names of all the registered types. Short names are
+// used unless they're ambiguous.
+func (reg *Registry) Names() []string {
+	var result []string
+
+	for _, name := range reg.names() {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// Implementers returns the sorted names of the registered types which
+// implement the given interface type either by value or by pointer receivers.
+// Short names are used unless they're ambiguous. Nil is returned if the given
+// type isn't an interface.
+func (reg *Registry) Implementers(iface reflect.Type) []string {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return nil
+	}
+
+	var result []string
+
+	for typ, name := range reg.names() {
+		if typ.Implements(iface) || reflect.PtrTo(typ).Implements(iface) {
+			result = append(result, name)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// Lookup returns the type associated with the given name. Fully qualified
+// names have precedence over aliases which have precedence over short
+// names. An error listing the candidates is returned if the name is an
+// ambiguous short name.
+func (reg *Registry) Lookup(name string) (reflect.Type, error) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	if typ, ok := reg.types[name]; ok {
+		return typ, nil
+	}
 
-	return typ, ok
+	if typ, ok := reg.aliases[name]; ok {
+		return typ, nil
+	}
+
+	switch candidates := reg.shorts[name]; len(candidates) {
+
+	case 0:
+		var names []string
+		for name := range reg.types {
+			names = append(names, name)
+		}
+		for name := range reg.shorts {
+			names = append(names, name)
+		}
+		for name := range reg.aliases {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown type '%s'%s", name, suggest(name, names))
+
+	case 1:
+		return candidates[0], nil
+
+	default:
+		var names []string
+		for _, typ := range candidates {
+			names = append(names, qualifiedName(typ))
+		}
+
+		sort.Strings(names)
+		return nil, fmt.Errorf("ambiguous type '%s'; candidates: %s", name, strings.Join(names, ", "))
+	}
+}
+
+// Get returns the type associated with the given name or false as the second
+// parameter if no types exists for that name or if the name is ambiguous.
+func (reg *Registry) Get(name string) (reflect.Type, bool) {
+	typ, err := reg.Lookup(name)
+	return typ, err == nil
 }
 
 // New returns a pointer to a newly instantiated object associated with the
@@ -81,15 +348,168 @@ func (reg *Registry) New(name string) (interface{}, bool) {
 	return nil, false
 }
 
+// RegisterNamedConverter associates the given converter with the given name.
+// Named converters are selected on a per-field basis using the conv option of
+// the blueprint struct tag (eg. `blueprint:"conv=millis"`). Duplicate
+// registrations are fatal; use TryRegisterNamedConverter to handle them.
+func (reg *Registry) RegisterNamedConverter(name string, conv Converter) {
+	if err := reg.TryRegisterNamedConverter(name, conv); err != nil {
+		klog.KFatalf("blueprint.registry.error", "%s", err)
+	}
+}
+
+// TryRegisterNamedConverter associates the given converter with the given
+// name. Returns ErrNilRegistration if the converter is nil or a DuplicateError
+// if a converter is already associated with the name.
+func (reg *Registry) TryRegisterNamedConverter(name string, conv Converter) error {
+	if conv == nil {
+		return ErrNilRegistration
+	}
+
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	if reg.convs == nil {
+		reg.convs = make(map[string]Converter)
+	}
+
+	if _, ok := reg.convs[name]; ok {
+		return &DuplicateError{Kind: "named converter", Name: name}
+	}
+
+	reg.convs[name] = conv
+	return nil
+}
+
+// NamedConverter returns the converter associated with the given name or false
+// as the second parameter if no converters exists for that name.
+func (reg *Registry) NamedConverter(name string) (Converter, bool) {
+	reg.mutex.Lock()
+
+	conv, ok := reg.convs[name]
+
+	reg.mutex.Unlock()
+
+	return conv, ok
+}
+
+// NamedConverters returns the sorted names of all the named converters.
+func (reg *Registry) NamedConverters() []string {
+	reg.mutex.Lock()
+
+	var names []string
+	for name := range reg.convs {
+		names = append(names, name)
+	}
+
+	reg.mutex.Unlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// RegisterSetter associates the given key with the named method of the given
+// value's type. The method must take a single argument and either return
+// nothing or an error. The loader calls the method with the value of the key
+// instead of setting a field. Invalid or duplicate registrations are fatal; use
+// TryRegisterSetter to handle them.
+func (reg *Registry) RegisterSetter(value interface{}, key, method string) {
+	if err := reg.TryRegisterSetter(value, key, method); err != nil {
+		klog.KFatalf("blueprint.registry.error", "%s", err)
+	}
+}
+
+// TryRegisterSetter associates the given key with the named method of the
+// given value's type. Returns ErrNilRegistration if the value is nil, a
+// DuplicateError if a setter is already associated with the key or an error if
+// the method isn't a valid setter.
+func (reg *Registry) TryRegisterSetter(value interface{}, key, method string) error {
+	typ := indirectType(value)
+	if typ == nil {
+		return ErrNilRegistration
+	}
+
+	if fn, ok := reflect.PtrTo(typ).MethodByName(method); !ok || !isSetter(fn.Type) {
+		return fmt.Errorf("'%s' is not a valid setter method of '%s'", method, typ)
+	}
+
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	if reg.setters == nil {
+		reg.setters = make(map[reflect.Type]map[string]string)
+	}
+
+	if reg.setters[typ] == nil {
+		reg.setters[typ] = make(map[string]string)
+	}
+
+	if _, ok := reg.setters[typ][key]; ok {
+		return &DuplicateError{Kind: "setter", Name: typ.String() + "." + key}
+	}
+
+	reg.setters[typ][key] = method
+	return nil
+}
+
+// Setter returns the name of the setter method associated with the given key
+// of the given struct type or false as the second parameter if no setters were
+// registered.
+func (reg *Registry) Setter(typ reflect.Type, key string) (string, bool) {
+	reg.mutex.Lock()
+
+	method, ok := reg.setters[typ][key]
+
+	reg.mutex.Unlock()
+
+	return method, ok
+}
+
+func (reg *Registry) settersOf(typ reflect.Type) map[string]string {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	setters := make(map[string]string)
+	for key, method := range reg.setters[typ] {
+		setters[key] = method
+	}
+
+	return setters
+}
+
 // String returns the string representation of the registry suitable for
 // debugging.
 func (reg *Registry) String() string {
 	reg.mutex.Lock()
 
 	var keys []string
+	notes := make(map[string]string)
 
-	for key := range reg.types {
+	for key, typ := range reg.types {
 		keys = append(keys, key)
+
+		if meta, ok := reg.metas[typ]; ok {
+			notes[key] = meta.String()
+		}
+	}
+
+	for key, candidates := range reg.shorts {
+		if _, ok := reg.types[key]; ok {
+			continue
+		}
+
+		keys = append(keys, key)
+
+		if len(candidates) > 1 {
+			notes[key] = "(ambiguous)"
+		} else if meta, ok := reg.metas[candidates[0]]; ok {
+			notes[key] = meta.String()
+		}
+	}
+
+	for key, typ := range reg.aliases {
+		keys = append(keys, key)
+		notes[key] = "(alias of " + qualifiedName(typ) + ")"
 	}
 
 	reg.mutex.Unlock()
@@ -102,6 +522,11 @@ func (reg *Registry) String() string {
 	for _, key := range keys {
 		buffer.WriteString("\n    ")
 		buffer.WriteString(key)
+
+		if note := notes[key]; note != "" {
+			buffer.WriteString(" ")
+			buffer.WriteString(note)
+		}
 	}
 
 	buffer.WriteString("\n]")
@@ -117,6 +542,13 @@ var DefaultRegistry Registry
 // name.
 func Register(value interface{}) { DefaultRegistry.Register(value) }
 
+// TryRegister associates the given value's type with the short and fully
+// qualified name or returns an error if the type can't be registered.
+func TryRegister(value interface{}) error { return DefaultRegistry.TryRegister(value) }
+
+// Unregister removes the given value's type from the registry.
+func Unregister(value interface{}) bool { return DefaultRegistry.Unregister(value) }
+
 // Get returns the type associated with the given name or false as the second
 // parameter if no types exists for that name.
 func Get(name string) (reflect.Type, bool) { return DefaultRegistry.Get(name) }
@@ -125,6 +557,49 @@ func Get(name string) (reflect.Type, bool) { return DefaultRegistry.Get(name) }
 // given name or false as the second parameter if no types exists for that name.
 func New(name string) (interface{}, bool) { return DefaultRegistry.New(name) }
 
+// Lookup returns the type associated with the given name or an error if the
+// name is unknown or ambiguous.
+func Lookup(name string) (reflect.Type, error) { return DefaultRegistry.Lookup(name) }
+
+// Alias associates the given name with the given value's type which must
+// already be registered.
+func Alias(name string, value interface{}) { DefaultRegistry.Alias(name, value) }
+
+// TryAlias associates the given name with the given value's type or returns an
+// error if the alias can't be registered.
+func TryAlias(name string, value interface{}) error { return DefaultRegistry.TryAlias(name, value) }
+
+// Implementers returns the sorted names of the registered types which
+// implement the given interface type (eg. reflect.TypeOf((*Handler)(nil)).Elem()).
+func Implementers(iface reflect.Type) []string { return DefaultRegistry.Implementers(iface) }
+
+// RegisterSetter associates the given key with the named method of the given
+// value's type.
+func RegisterSetter(value interface{}, key, method string) {
+	DefaultRegistry.RegisterSetter(value, key, method)
+}
+
+// TryRegisterSetter associates the given key with the named method of the
+// given value's type or returns an error if the setter can't be registered.
+func TryRegisterSetter(value interface{}, key, method string) error {
+	return DefaultRegistry.TryRegisterSetter(value, key, method)
+}
+
+// RegisterNamedConverter associates the given converter with the given name.
+func RegisterNamedConverter(name string, conv Converter) {
+	DefaultRegistry.RegisterNamedConverter(name, conv)
+}
+
+// TryRegisterNamedConverter associates the given converter with the given name
+// or returns an error if the converter can't be registered.
+func TryRegisterNamedConverter(name string, conv Converter) error {
+	return DefaultRegistry.TryRegisterNamedConverter(name, conv)
+}
+
+// NamedConverter returns the converter associated with the given name or false
+// as the second parameter if no converters exists for that name.
+func NamedConverter(name string) (Converter, bool) { return DefaultRegistry.NamedConverter(name) }
+
 func init() {
 	Register(int(0))
 	Register(int8(0))
@@ -0,0 +1,79 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint_test
+
+import (
+	"github.com/RAttab/goblueprint/blueprint"
+
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Impl conflicts with the Impl type of the blueprint package tests.
+type Impl struct{ S string }
+
+func TestRegistry_Ambiguous(t *testing.T) {
+	reg := &blueprint.Registry{}
+	reg.Register(blueprint.Impl{})
+	reg.Register(Impl{})
+	reg.Register(blueprint.Struct{})
+	reg.Alias("OldImpl", Impl{})
+
+	if _, err := reg.Lookup("Impl"); err == nil {
+		t.Errorf("FAIL: expected ambiguity error")
+
+	} else if exp := "ambiguous type 'Impl'; candidates: github.com/RAttab/goblueprint/blueprint/Impl, github.com/RAttab/goblueprint/blueprint_test/Impl"; err.Error() != exp {
+		t.Errorf("FAIL: error '%s' != '%s'", err, exp)
+	}
+
+	for name, exp := range map[string]reflect.Type{
+		"Struct":  reflect.TypeOf(blueprint.Struct{}),
+		"OldImpl": reflect.TypeOf(Impl{}),
+		"github.com/RAttab/goblueprint/blueprint/Impl":      reflect.TypeOf(blueprint.Impl{}),
+		"github.com/RAttab/goblueprint/blueprint_test/Impl": reflect.TypeOf(Impl{}),
+	} {
+		if typ, err := reg.Lookup(name); err != nil || typ != exp {
+			t.Errorf("FAIL(%s): %v != %v (%v)", name, typ, exp, err)
+		}
+	}
+
+	if _, err := reg.Lookup("Strukt"); err == nil || !strings.Contains(err.Error(), "did you mean 'Struct'?") {
+		t.Errorf("FAIL: unexpected error for unknown type: %v", err)
+	}
+
+	loader := &blueprint.Loader{Values: make(map[string]interface{}), Registry: reg}
+	values, err := loader.LoadJSON([]byte(`{
+        "@import": {
+            "b": "github.com/RAttab/goblueprint/blueprint",
+            "t": "github.com/RAttab/goblueprint/blueprint_test"
+        },
+        "a!b.Impl": { "I": 1 },
+        "b!t.Impl": { "S": "blah" },
+        "c!OldImpl": { "S": "bleh" }
+    }`))
+
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	exp := map[string]interface{}{
+		"a": &blueprint.Impl{I: 1},
+		"b": &Impl{S: "blah"},
+		"c": &Impl{S: "bleh"},
+	}
+
+	if !reflect.DeepEqual(values, exp) {
+		t.Errorf("FAIL: %v != %v", values, exp)
+	}
+
+	loader = &blueprint.Loader{Values: make(map[string]interface{}), Registry: reg}
+	if _, err := loader.LoadJSON([]byte(`{ "a!Impl": {} }`)); err == nil || !strings.Contains(err.Error(), "ambiguous type 'Impl'") {
+		t.Errorf("FAIL: expected ambiguity error got %v", err)
+	}
+
+	loader = &blueprint.Loader{Values: make(map[string]interface{}), Registry: reg}
+	if _, err := loader.LoadJSON([]byte(`{ "@import": { "b.t": "github.com/RAttab/goblueprint/blueprint" } }`)); err == nil || !strings.Contains(err.Error(), "invalid import prefix 'b.t'") {
+		t.Errorf("FAIL: expected invalid prefix error got %v", err)
+	}
+}
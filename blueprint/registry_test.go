@@ -0,0 +1,170 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegistry_TryRegister(t *testing.T) {
+	reg := &Registry{}
+
+	if err := reg.TryRegister(Impl{}); err != nil {
+		t.Errorf("FAIL: unexpected error: %s", err)
+	}
+
+	if err := reg.TryRegister(&Impl{}); err == nil {
+		t.Errorf("FAIL: expected duplicate error")
+
+	} else if dup, ok := err.(*DuplicateError); !ok {
+		t.Errorf("FAIL: unexpected error type '%T'", err)
+
+	} else if exp := "github.com/RAttab/goblueprint/blueprint/Impl"; dup.Kind != "type" || dup.Name != exp {
+		t.Errorf("FAIL: unexpected duplicate error: %+v", dup)
+	}
+
+	if err := reg.TryRegister(nil); err != ErrNilRegistration {
+		t.Errorf("FAIL: unexpected error for nil: %v", err)
+	}
+
+	clone := reg.Clone()
+	clone.Register(Struct{})
+	clone.Alias("OldImpl", Impl{})
+
+	if _, ok := reg.Get("Struct"); ok {
+		t.Errorf("FAIL: clone modified the original registry")
+	}
+
+	if !clone.Unregister(Impl{}) {
+		t.Errorf("FAIL: unable to unregister Impl")
+	}
+
+	if clone.Unregister(Impl{}) {
+		t.Errorf("FAIL: unregistered Impl twice")
+	}
+
+	for _, name := range []string{"Impl", "OldImpl"} {
+		if _, ok := clone.Get(name); ok {
+			t.Errorf("FAIL(%s): type still registered in clone", name)
+		}
+	}
+
+	if _, ok := reg.Get("Impl"); !ok {
+		t.Errorf("FAIL: unregister modified the original registry")
+	}
+
+	if err := clone.TryRegister(Impl{}); err != nil {
+		t.Errorf("FAIL: unable to register Impl after unregister: %s", err)
+	}
+
+	if clone.Unregister(nil) {
+		t.Errorf("FAIL: unregistered nil")
+	}
+
+	if err := clone.TryAlias("Nil", nil); err != ErrNilRegistration {
+		t.Errorf("FAIL: unexpected error for nil alias: %v", err)
+	}
+
+	if err := clone.TryAlias("Impl2", Impl{}); err != nil {
+		t.Errorf("FAIL: unable to alias Impl: %s", err)
+	}
+
+	if err := clone.TryAlias("Impl2", Impl{}); err == nil {
+		t.Errorf("FAIL: expected duplicate alias error")
+
+	} else if dup, ok := err.(*DuplicateError); !ok || dup.Kind != "alias" {
+		t.Errorf("FAIL: unexpected error: %v", err)
+	}
+
+	type Unregistered struct{}
+
+	if err := clone.TryAlias("Other", Unregistered{}); err == nil {
+		t.Errorf("FAIL: expected error for unregistered type")
+	}
+}
+
+func TestRegistry_TryRegisterOthers(t *testing.T) {
+	reg := &Registry{}
+	conv := ConverterFn(func(value interface{}) (interface{}, error) { return value, nil })
+	fns := &Functions{}
+
+	CheckDuplicate(t, "named converter",
+		func() error { return reg.TryRegisterNamedConverter("c", conv) })
+
+	CheckDuplicate(t, "setter",
+		func() error { return reg.TryRegisterSetter(Conn{}, "Tags", "AddTags") })
+
+	CheckDuplicate(t, "type",
+		func() error { return reg.TryRegisterMeta(Impl{}, Meta{Description: "blah"}) })
+
+	CheckDuplicate(t, "function",
+		func() error { return fns.TryRegister("f", func() {}) })
+
+	if meta, ok := reg.Meta(reflect.TypeOf(Impl{})); !ok || meta.Description != "blah" {
+		t.Errorf("FAIL: unexpected meta: %+v", meta)
+	}
+
+	if err := reg.TryRegisterSetter(Conn{}, "Host", "Host"); err == nil {
+		t.Errorf("FAIL: expected error for invalid setter")
+	}
+
+	if err := fns.TryRegister("g", 10); err == nil {
+		t.Errorf("FAIL: expected error for non-function")
+	}
+
+	for _, err := range []error{
+		reg.TryRegisterNamedConverter("nil", nil),
+		reg.TryRegisterSetter(nil, "Tags", "AddTags"),
+		reg.TryRegisterMeta(nil, Meta{}),
+		fns.TryRegister("nil", nil),
+	} {
+		if err != ErrNilRegistration {
+			t.Errorf("FAIL: unexpected error for nil: %v", err)
+		}
+	}
+}
+
+// CheckDuplicate calls the given registration twice and checks that the second
+// call returns a DuplicateError of the given kind.
+func CheckDuplicate(t *testing.T, kind string, register func() error) {
+	if err := register(); err != nil {
+		t.Errorf("FAIL(%s): unexpected error: %s", kind, err)
+	}
+
+	if err := register(); err == nil {
+		t.Errorf("FAIL(%s): expected duplicate error", kind)
+
+	} else if dup, ok := err.(*DuplicateError); !ok || dup.Kind != kind {
+		t.Errorf("FAIL(%s): unexpected error: %v", kind, err)
+	}
+}
+
+func TestRegistry_TryRegisterConverter(t *testing.T) {
+	type Conv struct{ S string }
+
+	conv := ConverterFn(func(value interface{}) (interface{}, error) {
+		return Conv{S: value.(string)}, nil
+	})
+
+	if err := TryRegisterConverter(Conv{}, conv); err != nil {
+		t.Errorf("FAIL: unexpected error: %s", err)
+	}
+
+	if err := TryRegisterConverter(Conv{}, conv); err == nil {
+		t.Errorf("FAIL: expected duplicate error")
+
+	} else if dup, ok := err.(*DuplicateError); !ok || dup.Kind != "converter" {
+		t.Errorf("FAIL: unexpected error: %v", err)
+	}
+
+	if !UnregisterConverter(Conv{}) || UnregisterConverter(Conv{}) {
+		t.Errorf("FAIL: unexpected unregister result")
+	}
+
+	if err := TryRegisterConverter(Conv{}, conv); err != nil {
+		t.Errorf("FAIL: unable to register converter after unregister: %s", err)
+	}
+
+	UnregisterConverter(Conv{})
+}
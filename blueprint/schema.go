@@ -0,0 +1,383 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema returns a JSON Schema (draft-07) document describing the blueprints
+// that can be loaded through LoadJSON using the types of the registry. The
+// document can be serialized using encoding/json and handed to editors for
+// validation and auto-completion.
+//
+// Top-level keys of the form name!Type and struct fields of interface type of
+// the form Field!Type enumerate the registered types that are valid for them.
+// Struct fields are described using their blueprint struct tag options: the
+// name option is used as the key while the default, required, min, max,
+// pattern and oneof options are translated to their JSON Schema equivalent.
+func (reg *Registry) Schema() map[string]interface{} {
+	gen := &schemaGen{reg: reg, names: reg.names(), defs: make(map[string]interface{})}
+
+	var names []string
+	types := make(map[string]reflect.Type)
+
+	for typ, name := range gen.names {
+		names = append(names, name)
+		types[name] = typ
+	}
+
+	sort.Strings(names)
+
+	patterns := map[string]interface{}{"^#": linkSchema}
+	for _, name := range names {
+		patterns["^[^!#@]+!"+regexp.QuoteMeta(name)+"$"] = gen.schema(types[name])
+	}
+
+	return map[string]interface{}{
+		"$schema":           "http://json-schema.org/draft-07/schema#",
+		"type":              "object",
+		"patternProperties": patterns,
+		"definitions":       gen.defs,
+	}
+}
+
+// Schema returns a JSON Schema document describing the blueprints that can be
+// loaded using the types of the default registry.
+func Schema() map[string]interface{} { return DefaultRegistry.Schema() }
+
+var linkSchema = map[string]interface{}{
+	"description": "link to the object at the given path",
+	"type":        []string{"string", "array"},
+	"items":       map[string]interface{}{"type": "string"},
+}
+
+var callsSchema = map[string]interface{}{
+	"description": "methods called once all links are resolved",
+	"type":        "array",
+	"items": map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"type": "array"},
+	},
+}
+
+type schemaGen struct {
+	reg   *Registry
+	names map[reflect.Type]string
+	defs  map[string]interface{}
+}
+
+func (gen *schemaGen) schema(typ reflect.Type) map[string]interface{} {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if conv, ok := lookupConverter(typ); ok {
+		if enum, ok := conv.(*EnumConverter); ok && !enum.Flags {
+			var values []interface{}
+			for _, name := range enum.names() {
+				values = append(values, name)
+			}
+			for _, name := range enum.names() {
+				values = append(values, enum.Names[name])
+			}
+			return map[string]interface{}{"enum": values}
+		}
+		return gen.convertible(typ)
+	}
+
+	if implements(typ, textUnmarshalerType) {
+		return map[string]interface{}{"type": "string"}
+	}
+
+	if implements(typ, jsonUnmarshalerType) {
+		return map[string]interface{}{}
+	}
+
+	switch typ.Kind() {
+
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": gen.schema(typ.Elem())}
+
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": gen.schema(typ.Elem())}
+
+	case reflect.Func:
+		schema := map[string]interface{}{"type": "string"}
+		if names := functionsOf(typ); len(names) > 0 {
+			schema["enum"] = names
+		}
+		return schema
+
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return gen.object(typ)
+		}
+
+		name := qualifiedName(typ)
+		if _, ok := gen.defs[name]; !ok {
+			gen.defs[name] = map[string]interface{}{}
+			gen.defs[name] = gen.object(typ)
+		}
+
+		return map[string]interface{}{"$ref": "#/definitions/" + strings.Replace(name, "/", "~1", -1)}
+	}
+
+	return map[string]interface{}{}
+}
+
+// convertible returns the schema of a type with a registered converter which
+// accepts strings along with its natural representation.
+func (gen *schemaGen) convertible(typ reflect.Type) map[string]interface{} {
+	switch typ.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": []string{"string", "integer"}}
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": []string{"string", "number"}}
+	}
+
+	return map[string]interface{}{"type": "string"}
+}
+
+func (gen *schemaGen) object(typ reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{"@call": callsSchema}
+	patterns := map[string]interface{}{"^#": linkSchema}
+	var required []string
+
+	var renamed []interface{}
+
+	for _, field := range Fields(typ) {
+		keys := []string{field.Name}
+		if name, ok := field.Options["name"]; ok && name != field.Name {
+			keys = []string{name, field.Name}
+		}
+
+		schema := gen.field(field)
+
+		for _, key := range keys {
+			properties[key] = schema
+
+			if field.Type.Kind() == reflect.Interface {
+				for _, name := range gen.reg.Implementers(field.Type) {
+					impl, _ := gen.reg.Get(name)
+					patterns["^"+regexp.QuoteMeta(key+"!"+name)+"$"] = gen.schema(impl)
+				}
+			}
+		}
+
+		if _, ok := field.Options["required"]; !ok {
+			continue
+		}
+
+		if len(keys) == 1 {
+			required = append(required, keys[0])
+			continue
+		}
+
+		var anyOf []interface{}
+		for _, key := range keys {
+			anyOf = append(anyOf, map[string]interface{}{"required": []string{key}})
+		}
+		renamed = append(renamed, map[string]interface{}{"anyOf": anyOf})
+	}
+
+	ptr := reflect.PtrTo(typ)
+	for i := 0; i < ptr.NumMethod(); i++ {
+		method := ptr.Method(i)
+		if !strings.HasPrefix(method.Name, "Set") || !isSetter(method.Type) {
+			continue
+		}
+
+		if key := method.Name[len("Set"):]; properties[key] == nil {
+			properties[key] = gen.schema(method.Type.In(1))
+		}
+	}
+
+	for key, name := range gen.reg.settersOf(typ) {
+		if method, ok := ptr.MethodByName(name); ok {
+			properties[key] = gen.schema(method.Type.In(1))
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"patternProperties":    patterns,
+		"additionalProperties": false,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	if len(renamed) > 0 {
+		schema["allOf"] = renamed
+	}
+
+	if typ.Name() != "" {
+		schema["title"] = typ.Name()
+	}
+
+	if meta, ok := gen.reg.Meta(typ); ok {
+		if meta.Description != "" {
+			schema["description"] = meta.Description
+		}
+		if notice := meta.deprecation(); notice != "" {
+			schema["deprecated"] = true
+			schema["$comment"] = "deprecated: " + notice
+		}
+	}
+
+	return schema
+}
+
+func (gen *schemaGen) field(field Field) map[string]interface{} {
+	schema := make(map[string]interface{})
+	for key, value := range gen.schema(field.Type) {
+		schema[key] = value
+	}
+
+	if _, ok := field.Options["unit"]; ok {
+		schema = gen.convertible(field.Type)
+	} else if _, ok := field.Options["conv"]; ok {
+		schema = gen.convertible(field.Type)
+	}
+
+	if value, ok := field.Default(); ok {
+		schema["default"] = schemaValue(field.Type, value)
+	}
+
+	if notice, ok := field.Options["deprecated"]; ok {
+		schema["deprecated"] = true
+		if notice != "" {
+			schema["$comment"] = "deprecated: " + notice
+		}
+	}
+
+	if pattern, ok := field.Options["pattern"]; ok {
+		schema["pattern"] = pattern
+	}
+
+	if oneof, ok := field.Options["oneof"]; ok {
+		var values []interface{}
+		for _, item := range strings.Split(oneof, "|") {
+			values = append(values, schemaValue(field.Type, item))
+			if number, ok := enumValue(field.Type, item); ok {
+				values = append(values, number)
+			}
+		}
+		schema["enum"] = values
+	}
+
+	typ := field.Type
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	for _, bound := range []struct{ option, number, length, items, properties string }{
+		{"min", "minimum", "minLength", "minItems", "minProperties"},
+		{"max", "maximum", "maxLength", "maxItems", "maxProperties"},
+	} {
+		value, ok := field.Options[bound.option]
+		if !ok {
+			continue
+		}
+
+		switch typ.Kind() {
+		case reflect.String:
+			schema[bound.length] = schemaValue(reflect.TypeOf(0), value)
+		case reflect.Slice, reflect.Array:
+			schema[bound.items] = schemaValue(reflect.TypeOf(0), value)
+		case reflect.Map:
+			schema[bound.properties] = schemaValue(reflect.TypeOf(0), value)
+		default:
+			if isNumeric(typ) && !hasConverter(typ) {
+				schema[bound.number] = schemaValue(typ, value)
+			}
+		}
+	}
+
+	return schema
+}
+
+func hasConverter(typ reflect.Type) bool {
+	_, ok := lookupConverter(typ)
+	return ok
+}
+
+// enumValue returns the numeric representation of the given struct tag value
+// for types with a converter which also accept numbers (eg. the value of a
+// name of an enum type).
+func enumValue(typ reflect.Type, value string) (interface{}, bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	conv, ok := lookupConverter(typ)
+	if !ok || !isNumeric(typ) {
+		return nil, false
+	}
+
+	if enum, ok := conv.(*EnumConverter); ok {
+		number, ok := enum.Names[value]
+		return number, ok
+	}
+
+	if obj, err := coerce(typ, value); err == nil {
+		return obj, true
+	}
+
+	return nil, false
+}
+
+// schemaValue converts the given struct tag value into its JSON representation
+// if it's a plain number or boolean. The value is returned as is otherwise.
+func schemaValue(typ reflect.Type, value string) interface{} {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if hasConverter(typ) || (!isNumeric(typ) && typ.Kind() != reflect.Bool) {
+		return value
+	}
+
+	obj, err := coerce(typ, value)
+	if err != nil {
+		return value
+	}
+
+	return obj
+}
+
+// functionsOf returns the sorted names of the functions of DefaultFunctions
+// which can be assigned to the given function type.
+func functionsOf(typ reflect.Type) []string {
+	var names []string
+
+	for _, name := range DefaultFunctions.Names() {
+		if fn, ok := DefaultFunctions.Get(name); ok && reflect.TypeOf(fn).AssignableTo(typ) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type Document struct {
+	Title  string `blueprint:"title,required,min=1,max=64,pattern=^[A-Z]"`
+	Pages  int    `blueprint:"default=1,min=1"`
+	Format string `blueprint:"oneof=pdf|html"`
+	Author Base
+	Level  Level
+	Min    Level `blueprint:"oneof=Info|Warning"`
+	Hash   HashFn
+	Tags   []string
+	Next   *Document
+}
+
+func TestSchema(t *testing.T) {
+	reg := &Registry{}
+	reg.Register(Document{})
+	reg.Register(Impl{})
+	reg.Register(Struct{})
+
+	schema := reg.Schema()
+
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("FAIL: unable to marshal schema: %s", err)
+	}
+
+	patterns := schema["patternProperties"].(map[string]interface{})
+	for _, key := range []string{"^#", `^[^!#@]+!Document$`, `^[^!#@]+!Impl$`, `^[^!#@]+!Struct$`} {
+		if _, ok := patterns[key]; !ok {
+			t.Errorf("FAIL: missing top-level pattern '%s'", key)
+		}
+	}
+
+	defs := schema["definitions"].(map[string]interface{})
+	doc := defs["github.com/RAttab/goblueprint/blueprint/Document"].(map[string]interface{})
+	props := doc["properties"].(map[string]interface{})
+
+	CheckSchema(t, "title", props["title"], map[string]interface{}{
+		"type": "string", "minLength": 1, "maxLength": 64, "pattern": "^[A-Z]",
+	})
+	CheckSchema(t, "Pages", props["Pages"], map[string]interface{}{
+		"type": "integer", "default": 1, "minimum": 1,
+	})
+	CheckSchema(t, "Format", props["Format"], map[string]interface{}{
+		"type": "string", "enum": []interface{}{"pdf", "html"},
+	})
+	CheckSchema(t, "Level", props["Level"], map[string]interface{}{
+		"enum": []interface{}{"Debug", "Info", "Warning", int64(0), int64(1), int64(2)},
+	})
+	CheckSchema(t, "Min", props["Min"].(map[string]interface{})["enum"], []interface{}{
+		"Info", int64(1), "Warning", int64(2),
+	})
+	CheckSchema(t, "Hash", props["Hash"], map[string]interface{}{
+		"type": "string", "enum": []string{"len"},
+	})
+	CheckSchema(t, "Next", props["Next"], map[string]interface{}{
+		"$ref": "#/definitions/github.com~1RAttab~1goblueprint~1blueprint~1Document",
+	})
+	CheckSchema(t, "Title", props["Title"], props["title"])
+	CheckSchema(t, "required", doc["allOf"], []interface{}{
+		map[string]interface{}{"anyOf": []interface{}{
+			map[string]interface{}{"required": []string{"title"}},
+			map[string]interface{}{"required": []string{"Title"}},
+		}},
+	})
+
+	docPatterns := doc["patternProperties"].(map[string]interface{})
+	for _, key := range []string{"^#", `^Author!Impl$`, `^Author!Struct$`} {
+		if _, ok := docPatterns[key]; !ok {
+			t.Errorf("FAIL: missing Document pattern '%s'", key)
+		}
+	}
+	if _, ok := docPatterns[`^Author!Document$`]; ok {
+		t.Errorf("FAIL: Document doesn't implement Base")
+	}
+}
+
+func CheckSchema(t *testing.T, title string, value, exp interface{}) {
+	if !reflect.DeepEqual(value, exp) {
+		t.Errorf("FAIL(%s): %#v != %#v", title, value, exp)
+	}
+}
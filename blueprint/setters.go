@@ -0,0 +1,147 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/gopath/path"
+
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// isSetter returns true if the given method type, which includes the
+// receiver, takes a single argument and returns either nothing or an error.
+func isSetter(typ reflect.Type) bool {
+	if typ.NumIn() != 2 {
+		return false
+	}
+	return typ.NumOut() == 0 || (typ.NumOut() == 1 && typ.Out(0) == errorType)
+}
+
+// setter returns the setter method of the given struct type associated with
+// the given key. Setters registered through Registry.RegisterSetter have
+// precedence over methods named after the key and prefixed by Set (eg.
+// SetTimeout for the Timeout key).
+func (loader *Loader) setter(typ reflect.Type, key string) (reflect.Method, bool) {
+	ptr := reflect.PtrTo(typ)
+
+	if name, ok := loader.registry().Setter(typ, key); ok {
+		return ptr.MethodByName(name)
+	}
+
+	policies := []MatchPolicy{MatchExact}
+	if loader.Match != MatchExact {
+		policies = append(policies, loader.Match)
+	}
+
+	for _, policy := range policies {
+		for i := 0; i < ptr.NumMethod(); i++ {
+			method := ptr.Method(i)
+
+			if !strings.HasPrefix(method.Name, "Set") || !isSetter(method.Type) {
+				continue
+			}
+
+			if policy.Match(key, method.Name[len("Set"):]) {
+				return method, true
+			}
+		}
+	}
+
+	return reflect.Method{}, false
+}
+
+// setterAt returns the setter associated with the last component of the given
+// path. Setters registered through Registry.RegisterSetter have precedence over
+// exported fields while other setters are only used if the component doesn't
+// refer to an exported field.
+func (loader *Loader) setterAt(dst path.P) (reflect.Method, bool) {
+	if len(dst) == 0 {
+		return reflect.Method{}, false
+	}
+
+	parent, key := dst[:len(dst)-1], dst[len(dst)-1]
+
+	typ, ok := loader.typeAt(parent)
+	if !ok || typ.Kind() != reflect.Struct {
+		return reflect.Method{}, false
+	}
+
+	if name, ok := loader.registry().Setter(typ, key); ok {
+		return reflect.PtrTo(typ).MethodByName(name)
+	}
+
+	if field, ok := typ.FieldByName(key); ok && field.PkgPath == "" {
+		return reflect.Method{}, false
+	}
+
+	return loader.setter(typ, key)
+}
+
+// decodeJSON converts a JSON subtree (eg. []interface{}) into a value of the
+// given type by going through its JSON representation.
+func decodeJSON(typ reflect.Type, value interface{}) (reflect.Value, error) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	obj := reflect.New(typ)
+	if err := json.Unmarshal(body, obj.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return obj.Elem(), nil
+}
+
+// callSetter calls the setter associated with the last component of the given
+// path as returned by setterAt. False is returned if no setters were found.
+func (loader *Loader) callSetter(dst path.P, value interface{}) (bool, error) {
+	method, ok := loader.setterAt(dst)
+	if !ok {
+		return false, nil
+	}
+
+	parent := dst[:len(dst)-1]
+	typ, _ := loader.typeAt(parent)
+
+	obj, err := parent.Get(loader.Values)
+	if err != nil {
+		return true, err
+	}
+
+	recv := reflect.ValueOf(obj)
+	if recv.Kind() != reflect.Ptr || recv.IsNil() {
+		return true, fmt.Errorf("setter '%s' requires a pointer to '%s'", method.Name, typ)
+	}
+
+	argType := method.Type.In(1)
+
+	if value, err = loader.convert(dst, argType, value); err != nil {
+		return true, err
+	}
+
+	arg := reflect.ValueOf(value)
+	if !arg.IsValid() {
+		arg = reflect.Zero(argType)
+
+	} else if kind := arg.Kind(); (kind == reflect.Map || kind == reflect.Slice) && !arg.Type().AssignableTo(argType) {
+		if arg, err = decodeJSON(argType, value); err != nil {
+			return true, err
+		}
+	}
+
+	if !arg.Type().AssignableTo(argType) {
+		return true, fmt.Errorf("invalid type '%s' for setter '%s' expecting '%s'", arg.Type(), method.Name, argType)
+	}
+
+	if out := method.Func.Call([]reflect.Value{recv, arg}); len(out) == 1 && !out[0].IsNil() {
+		return true, out[0].Interface().(error)
+	}
+
+	return true, nil
+}
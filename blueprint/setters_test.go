@@ -0,0 +1,96 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Conn struct {
+	Host string
+
+	timeout time.Duration
+	retries int
+	tags    []string
+}
+
+func (conn *Conn) SetTimeout(timeout time.Duration) { conn.timeout = timeout }
+
+func (conn *Conn) SetRetries(retries int) error {
+	if retries < 0 {
+		return fmt.Errorf("negative retries '%d'", retries)
+	}
+
+	conn.retries = retries
+	return nil
+}
+
+func (conn *Conn) AddTags(tags []string) { conn.tags = append(conn.tags, tags...) }
+
+type Label struct {
+	Value string
+}
+
+func (label *Label) SetLower(value string) { label.Value = strings.ToLower(value) }
+
+func init() {
+	Register(Conn{})
+	RegisterSetter(Conn{}, "Tags", "AddTags")
+
+	Register(Label{})
+	RegisterSetter(Label{}, "Value", "SetLower")
+}
+
+func TestSetters(t *testing.T) {
+	values, err := LoadJSON([]byte(`{
+        "a!Conn": { "Host": "blah", "Timeout": "5s", "Retries": 3, "Tags": [ "a", "b" ] }
+    }`))
+
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	exp := &Conn{Host: "blah", timeout: 5 * time.Second, retries: 3, tags: []string{"a", "b"}}
+
+	if conn := values["a"].(*Conn); !reflect.DeepEqual(conn, exp) {
+		t.Errorf("FAIL: %+v != %+v", conn, exp)
+	}
+
+	CheckLoadJSONError(t, `{ "a!Conn": { "Retries": -1 } }`, "negative retries '-1' at 'a.Retries'")
+	CheckLoadJSONError(t, `{ "a!Conn": { "Timeout": "5 sec" } }`, "at 'a.Timeout'")
+
+	loader := &Loader{Values: make(map[string]interface{}), Match: MatchFold, Strict: true, Roots: []string{"a"}}
+	if values, err := loader.LoadJSON([]byte(`{ "a!Conn": { "timeout": "1m" } }`)); err != nil {
+		t.Errorf("FAIL: unable to load json\n%v", err)
+
+	} else if conn := values.(map[string]interface{})["a"].(*Conn); conn.timeout != time.Minute {
+		t.Errorf("FAIL: timeout %s != 1m", conn.timeout)
+	}
+}
+
+func TestSetters_Registered(t *testing.T) {
+	conn := &Conn{}
+	loader := &Loader{Values: conn}
+
+	loader.TestAdd(t, "Tags", []string{"a", "b"})
+	loader.TestAdd(t, "Tags", []string{"c"})
+
+	if exp := []string{"a", "b", "c"}; !reflect.DeepEqual(conn.tags, exp) {
+		t.Errorf("FAIL: tags %v != %v", conn.tags, exp)
+	}
+}
+
+func TestSetters_RegisteredField(t *testing.T) {
+	values, err := LoadJSON([]byte(`{ "a!Label": { "Value": "BLAH" } }`))
+	if err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	if label := values["a"].(*Label); label.Value != "blah" {
+		t.Errorf("FAIL: value '%s' != 'blah'", label.Value)
+	}
+}
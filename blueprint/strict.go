@@ -0,0 +1,136 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/gopath/path"
+
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// checkKeys returns an error if a component of the given path doesn't match
+// an exported field of the struct it's applied to. Components that can't be
+// typed yet (eg. nil interfaces) are ignored.
+func (loader *Loader) checkKeys(src path.P) error {
+	for i := 0; i < len(src); i++ {
+		typ, ok := loader.typeAt(src[:i])
+		if !ok {
+			return nil
+		}
+
+		if typ.Kind() != reflect.Struct {
+			continue
+		}
+
+		if i == len(src)-1 {
+			if _, ok := loader.setter(typ, src[i]); ok {
+				return nil
+			}
+		}
+
+		if field, ok := typ.FieldByName(src[i]); !ok || field.PkgPath != "" {
+			var names []string
+			for _, field := range Fields(typ) {
+				names = append(names, field.Name)
+			}
+
+			return fmt.Errorf("unknown field '%s' in '%s'%s", src[i], typ, suggest(src[i], names))
+		}
+	}
+
+	return nil
+}
+
+// typeAt returns the dynamic type of the object at the given path with all
+// pointers dereferenced or false if the type can't be determined.
+func (loader *Loader) typeAt(src path.P) (reflect.Type, bool) {
+	typ, err := src.Type(loader.Values)
+	if err != nil {
+		return nil, false
+	}
+
+	if typ.Kind() == reflect.Interface {
+		value, err := src.Get(loader.Values)
+		if err != nil || value == nil {
+			return nil, false
+		}
+		typ = reflect.TypeOf(value)
+	}
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ, true
+}
+
+// checkUnused reports the top-level keys of a map root which are never used.
+// If Roots is empty, a key is used if it's the target of a link or of a link
+// argument of a call. Otherwise, a key is used if it's reachable from the keys
+// listed in Roots through links and calls in which case the links whose source
+// is within an unused key are also reported, whatever their depth.
+func (loader *Loader) checkUnused() {
+	root := reflect.ValueOf(loader.Values)
+	if root.Kind() != reflect.Map || root.Type().Key().Kind() != reflect.String {
+		return
+	}
+
+	type ref struct{ src, target string }
+	var refs []ref
+
+	for src, target := range loader.links {
+		if src := path.New(src); len(src) > 0 && len(target) > 0 {
+			refs = append(refs, ref{src[0], target[0]})
+		}
+	}
+
+	for _, call := range loader.calls {
+		for _, arg := range call.args {
+			if target, ok := arg.(path.P); ok && len(call.src) > 0 && len(target) > 0 {
+				refs = append(refs, ref{call.src[0], target[0]})
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, key := range loader.Roots {
+		used[key] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, ref := range refs {
+			if (len(loader.Roots) == 0 || used[ref.src]) && !used[ref.target] {
+				used[ref.target] = true
+				changed = true
+			}
+		}
+	}
+
+	unused := make(map[string]string)
+	for _, key := range root.MapKeys() {
+		unused[key.String()] = "object"
+	}
+	for src := range loader.links {
+		src := path.New(src)
+		if len(src) == 1 && unused[src[0]] != "" || len(src) > 1 && len(loader.Roots) > 0 {
+			unused[src.String()] = "link"
+		}
+	}
+
+	var keys []string
+	for key := range unused {
+		if !used[path.New(key)[0]] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		loader.ErrorAt(fmt.Errorf("unused %s '%s'", unused[key], key), path.New(key))
+	}
+}
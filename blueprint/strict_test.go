@@ -0,0 +1,112 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	json := `{
+        "a!Struct": { "I": 10, "Base!Impl": { "I": 20, "Ss": "blah" } },
+        "b!Struct": { "Z": 10 },
+        "c!Impl": { "I": 1 },
+        "d!Struct": { "#Base": "c", "#Q": "c" },
+        "#e": "c.I",
+        "f": "unused",
+        "g!Struct": { "#Base": "h" },
+        "h!Impl": { "I": 2 }
+    }`
+
+	loader := &Loader{Values: make(map[string]interface{}), Strict: true, Roots: []string{"d"}}
+
+	_, err := loader.LoadJSON([]byte(json))
+	if err == nil {
+		t.Fatalf("FAIL: expected strict errors")
+	}
+
+	for _, exp := range []string{
+		"unknown field 'Ss' in 'blueprint.Impl'; did you mean 'S'? at 'a.Base.Ss'",
+		"unknown field 'Z' in 'blueprint.Struct' at 'b.Z'",
+		"unknown field 'Q' in 'blueprint.Struct' at 'd.Q'",
+		"unused object 'a' at 'a'",
+		"unused object 'b' at 'b'",
+		"unused link 'e' at 'e'",
+		"unused object 'f' at 'f'",
+		"unused object 'g' at 'g'",
+		"unused link 'g.Base' at 'g.Base'",
+		"unused object 'h' at 'h'",
+	} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Errorf("FAIL: missing error '%s' in:\n%s", exp, err)
+		}
+	}
+
+	if n := len(err.(Errors)); n != 10 {
+		t.Errorf("FAIL: expected 10 errors got %d:\n%s", n, err)
+	}
+
+	loader = &Loader{Values: make(map[string]interface{}), Strict: true, Roots: []string{"multi"}}
+	if _, err := loader.LoadJSON([]byte(`{
+        "hello!Impl": { "S": "hello" },
+        "multi!Struct": { "#Base": "hello" }
+    }`)); err != nil {
+		t.Errorf("FAIL: unexpected strict errors\n%s", err)
+	}
+}
+
+func TestStrict_NoRoots(t *testing.T) {
+	json := `{
+        "a!Struct": { "#Base": "b", "@call": [ { "Eq": [ "#c" ] } ] },
+        "b!Impl": { "I": 1 },
+        "c!Impl": { "S": "c" },
+        "#d": "b",
+        "e": "unused"
+    }`
+
+	loader := &Loader{Values: make(map[string]interface{}), Strict: true}
+
+	_, err := loader.LoadJSON([]byte(json))
+	if err == nil {
+		t.Fatalf("FAIL: expected strict errors")
+	}
+
+	for _, exp := range []string{
+		"unused object 'a' at 'a'",
+		"unused link 'd' at 'd'",
+		"unused object 'e' at 'e'",
+	} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Errorf("FAIL: missing error '%s' in:\n%s", exp, err)
+		}
+	}
+
+	if n := len(err.(Errors)); n != 3 {
+		t.Errorf("FAIL: expected 3 errors got %d:\n%s", n, err)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		candidates []string
+		exp        string
+	}{
+		{"lenn", []string{"len", "less"}, "len"},
+		{"Ss", []string{"I", "S"}, "S"},
+		{"Handel", []string{"Handle", "Close"}, "Handle"},
+		{"Z", []string{"I", "S"}, ""},
+		{"Qx", []string{"I", "S"}, ""},
+		{"blah", []string{"Handle", "Close"}, ""},
+	} {
+		exp := ""
+		if test.exp != "" {
+			exp = "; did you mean '" + test.exp + "'?"
+		}
+
+		if result := suggest(test.name, test.candidates); result != exp {
+			t.Errorf("FAIL: suggest(%q, %v) -> %q != %q", test.name, test.candidates, result, exp)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"fmt"
+)
+
+// suggest returns a "did you mean" hint for the candidate closest to the given
+// name or an empty string if none of the candidates are close enough. The
+// allowed distance scales with the length of the name and is capped below it
+// so that short names (eg. "Z") aren't matched against any short candidate by
+// replacing every character.
+func suggest(name string, candidates []string) string {
+	maxDist := len(name) / 3
+	if maxDist < 1 {
+		maxDist = 1
+	}
+	if maxDist >= len(name) {
+		maxDist = len(name) - 1
+	}
+
+	best, bestDist := "", maxDist+1
+
+	for _, candidate := range candidates {
+		if dist := distance(name, candidate); dist < bestDist {
+			best, bestDist = candidate, dist
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf("; did you mean '%s'?", best)
+}
+
+// distance returns the levenshtein distance between the two strings.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
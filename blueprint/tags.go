@@ -0,0 +1,104 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"reflect"
+	"strings"
+)
+
+// tag holds the options of a blueprint struct tag which is made of comma
+// separated options of the form key=value or key (eg. `blueprint:"unit=bytes"`).
+// Option values can't contain the ',' character.
+//
+// The first option can also be a plain name (eg. `blueprint:"max_body"`)
+// which is then accepted in place of the name of the field.
+//
+// The following options are currently supported:
+//
+//	name=<name>         equivalent to specifying a name as the first option.
+//	unit=<name>         converts the value using a unit (bytes, rate or percent).
+//	conv=<name>         converts the value using a named converter.
+//	default=<value>     default value applied when the object is instantiated.
+//	deprecated=<notice> emits a warning whenever the field is set. The notice is
+//	                    optional (eg. `blueprint:"deprecated"`).
+//
+// Constraints checked once loading is completed are also specified as options.
+// See Loader.Finish for more details.
+type tag map[string]string
+
+// tagFlags lists the options without values which can't be mistaken for the
+// name of the field when they're the first option of the tag.
+var tagFlags = map[string]bool{"required": true, "deprecated": true}
+
+func parseTag(field reflect.StructField) tag {
+	result := make(tag)
+
+	str, ok := field.Tag.Lookup("blueprint")
+	if !ok {
+		return result
+	}
+
+	for i, option := range strings.Split(str, ",") {
+		if j := strings.Index(option, "="); j >= 0 {
+			result[strings.TrimSpace(option[:j])] = option[j+1:]
+
+		} else if option = strings.TrimSpace(option); option == "" {
+			continue
+
+		} else if i == 0 && !tagFlags[option] {
+			result["name"] = option
+
+		} else {
+			result[option] = ""
+		}
+	}
+
+	return result
+}
+
+// Field describes a configurable field of a struct type along with the options
+// of its blueprint struct tag.
+type Field struct {
+	Name    string
+	Type    reflect.Type
+	Options map[string]string
+}
+
+// Default returns the default value of the field as specified by the default
+// option of its blueprint struct tag or false as the second parameter if the
+// field has no defaults.
+func (field Field) Default() (string, bool) {
+	value, ok := field.Options["default"]
+	return value, ok
+}
+
+// Fields returns the configurable fields of the given struct type or of the
+// struct type pointed to by the given type. Nil is returned for all other
+// types.
+func Fields(typ reflect.Type) []Field {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []Field
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fields = append(fields, Field{
+			Name:    field.Name,
+			Type:    field.Type,
+			Options: parseTag(field),
+		})
+	}
+
+	return fields
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Retry struct {
+	Attempts int           `blueprint:"default=3"`
+	Backoff  time.Duration `blueprint:"default=100ms"`
+}
+
+type Client struct {
+	Timeout time.Duration `blueprint:"default=30s"`
+	Host    string        `blueprint:"default=localhost"`
+	Buffer  int           `blueprint:"unit=bytes,default=4KiB"`
+	Level   Level         `blueprint:"default=Info"`
+	Retry   Retry
+	Backup  *Retry
+	Plain   int
+}
+
+type Pool struct {
+	Retries []Retry
+	ByName  map[string]Retry
+}
+
+func init() {
+	Register(Client{})
+	Register(Pool{})
+}
+
+func TestDefaults(t *testing.T) {
+	CheckLoadJSON(t, `{
+        "a!Client": { "Host": "example.com", "Backup": { "Attempts": 5 } },
+        "b!Client": { "Retry": { "Attempts": 1 } }
+    }`, map[string]interface{}{
+		"a": &Client{
+			Timeout: 30 * time.Second,
+			Host:    "example.com",
+			Buffer:  4096,
+			Level:   Info,
+			Retry:   Retry{Attempts: 3, Backoff: 100 * time.Millisecond},
+			Backup:  &Retry{Attempts: 5, Backoff: 100 * time.Millisecond},
+		},
+		"b": &Client{
+			Timeout: 30 * time.Second,
+			Host:    "localhost",
+			Buffer:  4096,
+			Level:   Info,
+			Retry:   Retry{Attempts: 1, Backoff: 100 * time.Millisecond},
+		},
+	})
+}
+
+func TestDefaults_Root(t *testing.T) {
+	client := &Client{Timeout: time.Minute}
+
+	if err := LoadJSONInto([]byte(`{ "Host": "example.com" }`), client); err != nil {
+		t.Fatalf("FAIL: unable to load json\n%v", err)
+	}
+
+	exp := &Client{
+		Timeout: time.Minute,
+		Host:    "example.com",
+		Buffer:  4096,
+		Level:   Info,
+		Retry:   Retry{Attempts: 3, Backoff: 100 * time.Millisecond},
+	}
+
+	if !reflect.DeepEqual(client, exp) {
+		t.Errorf("FAIL: %+v != %+v", client, exp)
+	}
+}
+
+func TestDefaults_Elements(t *testing.T) {
+	CheckLoadJSON(t, `{
+        "p!Pool": {
+            "Retries": [ { "Attempts": 1 }, { "Backoff": "1s" } ],
+            "ByName": { "x": { "Attempts": 2 } }
+        }
+    }`, map[string]interface{}{
+		"p": &Pool{
+			Retries: []Retry{
+				{Attempts: 1, Backoff: 100 * time.Millisecond},
+				{Attempts: 3, Backoff: time.Second},
+			},
+			ByName: map[string]Retry{"x": {Attempts: 2, Backoff: 100 * time.Millisecond}},
+		},
+	})
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields(reflect.TypeOf(&Retry{}))
+
+	exp := []Field{
+		{Name: "Attempts", Type: reflect.TypeOf(0), Options: map[string]string{"default": "3"}},
+		{Name: "Backoff", Type: reflect.TypeOf(time.Duration(0)), Options: map[string]string{"default": "100ms"}},
+	}
+
+	if !reflect.DeepEqual(fields, exp) {
+		t.Errorf("FAIL: %v != %v", fields, exp)
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	type Tagged struct {
+		Renamed int `blueprint:"renamed,required"`
+		Old     int `blueprint:"deprecated"`
+		Both    int `blueprint:"required,deprecated=use Renamed"`
+	}
+
+	for i, exp := range []tag{
+		{"name": "renamed", "required": ""},
+		{"deprecated": ""},
+		{"required": "", "deprecated": "use Renamed"},
+	} {
+		field := reflect.TypeOf(Tagged{}).Field(i)
+		if result := parseTag(field); !reflect.DeepEqual(result, exp) {
+			t.Errorf("FAIL(%s): %v != %v", field.Name, result, exp)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ByteSize represents a quantity of bytes which can be expressed using either
+// SI (eg. 10MB = 10 * 1000^2) or IEC (eg. 10MiB = 10 * 1024^2) units.
+type ByteSize int64
+
+var byteUnits = []struct {
+	name string
+	size int64
+}{
+	{"EiB", 1 << 60}, {"PiB", 1 << 50}, {"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+	{"EB", 1e18}, {"PB", 1e15}, {"TB", 1e12}, {"GB", 1e9}, {"MB", 1e6}, {"KB", 1e3},
+	{"B", 1},
+}
+
+// ParseByteSize parses a byte size made of a number followed by an optional
+// SI or IEC unit (eg. 512, 1.5KB, 10MiB). Units are case insensitive.
+func ParseByteSize(str string) (ByteSize, error) {
+	num, unit := splitUnit(str)
+
+	value, ok := new(big.Rat).SetString(num)
+	if !ok || strings.Contains(num, "/") {
+		return 0, fmt.Errorf("invalid byte size '%s'", str)
+	}
+
+	if value.Sign() < 0 {
+		return 0, fmt.Errorf("byte size '%s' is negative", str)
+	}
+
+	size := int64(1)
+	if unit != "" {
+		size = 0
+		for _, byteUnit := range byteUnits {
+			if strings.EqualFold(unit, byteUnit.name) {
+				size = byteUnit.size
+				break
+			}
+		}
+
+		if size == 0 {
+			return 0, fmt.Errorf("unknown byte size unit '%s' in '%s'", unit, str)
+		}
+	}
+
+	value.Mul(value, new(big.Rat).SetInt64(size))
+
+	if !value.IsInt() {
+		return 0, fmt.Errorf("byte size '%s' is not a whole number of bytes", str)
+	}
+
+	if !value.Num().IsInt64() {
+		return 0, fmt.Errorf("byte size '%s' overflows int64", str)
+	}
+
+	return ByteSize(value.Num().Int64()), nil
+}
+
+// String formats the byte size using the largest unit that represents it
+// exactly.
+func (size ByteSize) String() string {
+	for _, byteUnit := range byteUnits {
+		unit := byteUnit.size
+		if size != 0 && int64(size)%unit == 0 {
+			return fmt.Sprintf("%d%s", int64(size)/unit, byteUnit.name)
+		}
+	}
+	return fmt.Sprintf("%dB", int64(size))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (size ByteSize) MarshalText() ([]byte, error) { return []byte(size.String()), nil }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (size *ByteSize) UnmarshalText(text []byte) (err error) {
+	*size, err = ParseByteSize(string(text))
+	return
+}
+
+// Rate represents a frequency in events per second which can be expressed
+// relative to any duration unit (eg. 500/s, 30/m, 1/10ms).
+type Rate float64
+
+// ParseRate parses a rate made of a number followed by the '/' character and a
+// duration. The duration's magnitude can be ommited (eg. /s is equivalent to
+// /1s).
+func ParseRate(str string) (Rate, error) {
+	i := strings.Index(str, "/")
+	if i < 0 {
+		return 0, fmt.Errorf("invalid rate '%s'; expected <count>/<duration>", str)
+	}
+
+	count, err := strconv.ParseFloat(strings.TrimSpace(str[:i]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate count in '%s'", str)
+	}
+
+	per := strings.TrimSpace(str[i+1:])
+	if per != "" && !unicode.IsDigit(rune(per[0])) {
+		per = "1" + per
+	}
+
+	duration, err := time.ParseDuration(per)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid rate duration in '%s'", str)
+	}
+
+	return Rate(count / duration.Seconds()), nil
+}
+
+// String formats the rate in events per second.
+func (rate Rate) String() string {
+	return strconv.FormatFloat(float64(rate), 'g', -1, 64) + "/s"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (rate Rate) MarshalText() ([]byte, error) { return []byte(rate.String()), nil }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (rate *Rate) UnmarshalText(text []byte) (err error) {
+	*rate, err = ParseRate(string(text))
+	return
+}
+
+// Percent represents a ratio which is expressed as a percentage (eg. 75% is
+// equivalent to 0.75).
+type Percent float64
+
+// ParsePercent parses a number followed by the '%' character into a ratio. A
+// number without the '%' suffix is interpreted as a ratio.
+func ParsePercent(str string) (Percent, error) {
+	num, scale := strings.TrimSpace(str), 1.0
+	if strings.HasSuffix(num, "%") {
+		num, scale = strings.TrimSpace(num[:len(num)-1]), 100.0
+	}
+
+	value, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage '%s'", str)
+	}
+
+	return Percent(value / scale), nil
+}
+
+// String formats the ratio as a percentage.
+func (percent Percent) String() string {
+	return strconv.FormatFloat(float64(percent)*100, 'g', -1, 64) + "%"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (percent Percent) MarshalText() ([]byte, error) { return []byte(percent.String()), nil }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (percent *Percent) UnmarshalText(text []byte) (err error) {
+	*percent, err = ParsePercent(string(text))
+	return
+}
+
+func splitUnit(str string) (num, unit string) {
+	str = strings.TrimSpace(str)
+
+	i := strings.LastIndexFunc(str, func(r rune) bool { return unicode.IsDigit(r) || r == '.' })
+	return strings.TrimSpace(str[:i+1]), strings.TrimSpace(str[i+1:])
+}
+
+// ByteSizeConverter converts string values into ByteSize values.
+func ByteSizeConverter(value interface{}) (interface{}, error) {
+	if str, ok := value.(string); ok {
+		return ParseByteSize(str)
+	}
+	return value, nil
+}
+
+// RateConverter converts string values into Rate values.
+func RateConverter(value interface{}) (interface{}, error) {
+	if str, ok := value.(string); ok {
+		return ParseRate(str)
+	}
+	return value, nil
+}
+
+// PercentConverter converts string values into Percent values.
+func PercentConverter(value interface{}) (interface{}, error) {
+	if str, ok := value.(string); ok {
+		return ParsePercent(str)
+	}
+	return value, nil
+}
+
+// units maps the names accepted by the unit struct tag option to their
+// converters. The unit option allows plain integer and float fields to be
+// configured using units (eg. `blueprint:"unit=bytes"`).
+var units = map[string]ConverterFn{
+	"bytes":   ByteSizeConverter,
+	"rate":    RateConverter,
+	"percent": PercentConverter,
+}
+
+// convertUnit converts the given value using the named unit and converts the
+// result to the given type while checking for overflows.
+func convertUnit(unit string, typ reflect.Type, value interface{}) (interface{}, error) {
+	conv, ok := units[unit]
+	if !ok {
+		return nil, fmt.Errorf("unknown unit '%s'", unit)
+	}
+
+	if _, ok := value.(string); !ok {
+		return convert(typ, value)
+	}
+
+	value, err := conv(value)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := reflect.ValueOf(value)
+
+	switch typ.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if obj.Kind() != reflect.Int64 {
+			return nil, fmt.Errorf("unit '%s' can't be assigned to '%s'", unit, typ)
+		}
+		if reflect.Zero(typ).OverflowInt(obj.Int()) {
+			return nil, fmt.Errorf("value '%v' overflows '%s'", value, typ)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if obj.Kind() != reflect.Int64 {
+			return nil, fmt.Errorf("unit '%s' can't be assigned to '%s'", unit, typ)
+		}
+		if obj.Int() < 0 || reflect.Zero(typ).OverflowUint(uint64(obj.Int())) {
+			return nil, fmt.Errorf("value '%v' overflows '%s'", value, typ)
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if obj.Kind() != reflect.Float64 {
+			return nil, fmt.Errorf("unit '%s' can't be assigned to '%s'", unit, typ)
+		}
+
+	default:
+		return nil, fmt.Errorf("unit '%s' can't be assigned to '%s'", unit, typ)
+	}
+
+	return obj.Convert(typ).Interface(), nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"strings"
+	"testing"
+)
+
+type Limits struct {
+	MaxBody  ByteSize
+	Rate     Rate
+	Ratio    Percent
+	Buffer   int32   `blueprint:"unit=bytes"`
+	Capacity uint64  `blueprint:"unit=bytes"`
+	Sampling float64 `blueprint:"unit=percent"`
+	Refill   float32 `blueprint:"unit=rate"`
+}
+
+type BadUnits struct {
+	Bogus int `blueprint:"unit=bogus"`
+	Count int `blueprint:"unit=rate"`
+}
+
+func init() {
+	Register(Limits{})
+	Register(BadUnits{})
+}
+
+func TestUnits(t *testing.T) {
+	CheckLoadJSON(t, `{
+        "l!Limits": {
+            "MaxBody": "10MiB",
+            "Rate": "500/s",
+            "Ratio": "75%",
+            "Buffer": "64KiB",
+            "Capacity": "1.5GB",
+            "Sampling": "12.5%",
+            "Refill": "30/m"
+        },
+        "m!Limits": { "MaxBody": 1024, "Buffer": 10 }
+    }`, map[string]interface{}{
+		"l": &Limits{
+			MaxBody:  10 << 20,
+			Rate:     500,
+			Ratio:    0.75,
+			Buffer:   64 << 10,
+			Capacity: 1500000000,
+			Sampling: 0.125,
+			Refill:   0.5,
+		},
+		"m": &Limits{MaxBody: 1024, Buffer: 10},
+	})
+
+	CheckLoadJSONError(t, `{ "l!Limits": { "MaxBody": "10XB" } }`, "unknown byte size unit 'XB'")
+	CheckLoadJSONError(t, `{ "l!Limits": { "MaxBody": "8EiB" } }`, "overflows int64")
+	CheckLoadJSONError(t, `{ "l!Limits": { "Buffer": "2GiB" } }`, "overflows 'int32'")
+	CheckLoadJSONError(t, `{ "l!Limits": { "Capacity": "-1KB" } }`, "byte size '-1KB' is negative")
+	CheckLoadJSONError(t, `{ "l!Limits": { "MaxBody": "-1KB" } }`, "byte size '-1KB' is negative")
+	CheckLoadJSONError(t, `{ "l!Limits": { "Rate": "500" } }`, "expected <count>/<duration>")
+	CheckLoadJSONError(t, `{ "b!BadUnits": { "Bogus": 10 } }`, "unknown unit 'bogus'")
+	CheckLoadJSONError(t, `{ "b!BadUnits": { "Count": "30/m" } }`, "unit 'rate' can't be assigned to 'int'")
+}
+
+func TestUnits_String(t *testing.T) {
+	for _, test := range []struct {
+		value interface{}
+		exp   string
+	}{
+		{ByteSize(0), "0B"},
+		{ByteSize(10 << 20), "10MiB"},
+		{ByteSize(1500000000), "1500MB"},
+		{ByteSize(1023), "1023B"},
+		{Rate(0.5), "0.5/s"},
+		{Percent(0.125), "12.5%"},
+	} {
+		if str := test.value.(interface{ String() string }).String(); str != test.exp {
+			t.Errorf("FAIL: {%T, %v} formatted as '%s' != '%s'", test.value, test.value, str, test.exp)
+		}
+	}
+}
+
+func TestUnits_ByteSizePrecision(t *testing.T) {
+	for str, exp := range map[string]ByteSize{
+		"9007199254740993B":   9007199254740993,
+		"9223372036854775807": 9223372036854775807,
+		"1.5KiB":              1536,
+		"1e3KB":               1000000,
+	} {
+		if size, err := ParseByteSize(str); err != nil || size != exp {
+			t.Errorf("FAIL(%s): %d != %d (%v)", str, size, exp, err)
+		}
+	}
+
+	for str, exp := range map[string]string{
+		"9223372036854775808B": "overflows int64",
+		"1.5B":                 "not a whole number of bytes",
+		"1/2KB":                "invalid byte size",
+	} {
+		if _, err := ParseByteSize(str); err == nil || !strings.Contains(err.Error(), exp) {
+			t.Errorf("FAIL(%s): error '%v' doesn't contain '%s'", str, err, exp)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// validate checks the value of the given field against the constraints
+// specified in the field's blueprint struct tag:
+//
+//	required        the value must not be the zero value.
+//	min=<value>     lower bound of a number or of the length of a string, slice or map.
+//	max=<value>     upper bound of a number or of the length of a string, slice or map.
+//	pattern=<regex> strings must match the regular expression.
+//	oneof=<a|b|...> the value must be one of the '|' separated values.
+//
+// Constraints other than required are only checked for non-zero values.
+func validate(field Field, value reflect.Value) (errs []error) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			value = reflect.Value{}
+			break
+		}
+		value = value.Elem()
+	}
+
+	if !value.IsValid() || value.IsZero() {
+		if _, ok := field.Options["required"]; ok {
+			errs = append(errs, fmt.Errorf("missing required value"))
+		}
+		return
+	}
+
+	if bound, ok := field.Options["min"]; ok {
+		if cmp, err := compare(value, bound); err != nil {
+			errs = append(errs, err)
+		} else if cmp < 0 {
+			errs = append(errs, fmt.Errorf("value '%v' is below the minimum of '%s'", value, bound))
+		}
+	}
+
+	if bound, ok := field.Options["max"]; ok {
+		if cmp, err := compare(value, bound); err != nil {
+			errs = append(errs, err)
+		} else if cmp > 0 {
+			errs = append(errs, fmt.Errorf("value '%v' is above the maximum of '%s'", value, bound))
+		}
+	}
+
+	if pattern, ok := field.Options["pattern"]; ok {
+		if value.Kind() != reflect.String {
+			errs = append(errs, fmt.Errorf("pattern constraint can't be applied to '%s'", value.Type()))
+
+		} else if re, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid pattern '%s': %s", pattern, err))
+
+		} else if !re.MatchString(value.String()) {
+			errs = append(errs, fmt.Errorf("value '%s' doesn't match the pattern '%s'", value, pattern))
+		}
+	}
+
+	if oneof, ok := field.Options["oneof"]; ok {
+		str, found := fmt.Sprint(value.Interface()), false
+
+		for _, item := range strings.Split(oneof, "|") {
+			if found = item == str; found {
+				break
+			}
+		}
+
+		if !found {
+			errs = append(errs, fmt.Errorf("value '%s' is not one of '%s'", str, oneof))
+		}
+	}
+
+	return
+}
+
+// compare returns the sign of the difference between the given value and the
+// given bound. The length is compared for strings, slices and maps while the
+// bound is converted to the value's type for numbers.
+func compare(value reflect.Value, bound string) (int, error) {
+	switch value.Kind() {
+
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		n, err := strconv.Atoi(bound)
+		if err != nil {
+			return 0, fmt.Errorf("invalid length bound '%s'", bound)
+		}
+		return big.NewInt(int64(value.Len())).Cmp(big.NewInt(int64(n))), nil
+	}
+
+	obj, err := coerceValue(value.Type(), bound)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bound '%s' for '%s': %s", bound, value.Type(), err)
+	}
+
+	switch value.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(value.Int()).Cmp(big.NewInt(obj.Int())), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(value.Uint()).Cmp(new(big.Int).SetUint64(obj.Uint())), nil
+
+	case reflect.Float32, reflect.Float64:
+		return big.NewFloat(value.Float()).Cmp(big.NewFloat(obj.Float())), nil
+	}
+
+	return 0, fmt.Errorf("bound constraints can't be applied to '%s'", value.Type())
+}
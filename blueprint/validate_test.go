@@ -0,0 +1,75 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type Server struct {
+	Name    string        `blueprint:"required,min=1,max=8,pattern=^[a-z]+$"`
+	Port    uint16        `blueprint:"required,min=1024"`
+	Workers int           `blueprint:"min=1,max=64"`
+	Timeout time.Duration `blueprint:"max=1m"`
+	Mode    string        `blueprint:"oneof=fast|slow"`
+	Peers   []*Server     `blueprint:"max=2"`
+}
+
+func init() { Register(Server{}) }
+
+func TestValidate(t *testing.T) {
+	a := &Server{Name: "blah", Port: 8080, Workers: 4, Timeout: 30 * time.Second, Mode: "fast"}
+	b := &Server{Name: "bleh", Port: 8081}
+	b.Peers = []*Server{a, b}
+
+	CheckLoadJSON(t, `{
+        "a!Server": { "Name": "blah", "Port": 8080, "Workers": 4, "Timeout": "30s", "Mode": "fast" },
+        "b!Server": { "Name": "bleh", "Port": 8081, "#Peers": [ "a", "b" ] }
+    }`, map[string]interface{}{
+		"a": a,
+		"b": b,
+	})
+
+	_, err := LoadJSON([]byte(`{
+        "a!Server": { "Name": "Blah", "Port": 80, "Workers": 100, "Timeout": "2m", "Mode": "medium" },
+        "b!Server": { "Name": "blahblahblah", "Peers": [ { "Name": "c" } ] }
+    }`))
+
+	if err == nil {
+		t.Fatalf("FAIL: expected validation errors")
+	}
+
+	for _, exp := range []string{
+		"value 'Blah' doesn't match the pattern '^[a-z]+$' at 'a.Name'",
+		"value '80' is below the minimum of '1024' at 'a.Port'",
+		"value '100' is above the maximum of '64' at 'a.Workers'",
+		"value '2m0s' is above the maximum of '1m' at 'a.Timeout'",
+		"value 'medium' is not one of 'fast|slow' at 'a.Mode'",
+		"is above the maximum of '8' at 'b.Name'",
+		"missing required value at 'b.Port'",
+		"missing required value at 'b.Peers.0.Port'",
+	} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Errorf("FAIL: missing error '%s' in:\n%s", exp, err)
+		}
+	}
+
+	if n := len(err.(Errors)); n != 8 {
+		t.Errorf("FAIL: expected 8 errors got %d:\n%s", n, err)
+	}
+}
+
+func TestValidate_Provided(t *testing.T) {
+	loader := NewLoader()
+	loader.Provide("peer", &Server{})
+
+	_, err := loader.LoadJSON([]byte(`{
+        "a!Server": { "Name": "blah", "Port": 8080, "#Peers": [ "@peer" ] }
+    }`))
+
+	if err != nil {
+		t.Errorf("FAIL: provided value was validated\n%s", err)
+	}
+}
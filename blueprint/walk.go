@@ -0,0 +1,97 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package blueprint
+
+import (
+	"github.com/RAttab/gopath/path"
+
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// WalkFn is called by Walk for every struct field reachable from the walked
+// value along with the path of the field and its value.
+type WalkFn func(src path.P, field Field, value reflect.Value)
+
+// Walk calls fn for every exported struct field reachable from the given
+// value by following pointers, interfaces, structs, maps, slices and
+// arrays. Each pointer is only visited once which protects against cycles and
+// avoids visiting linked objects multiple times. Map keys are visited in sorted
+// order.
+func Walk(value interface{}, fn WalkFn) {
+	walker := &walker{fn: fn, seen: make(map[visit]bool)}
+	walker.walk(nil, reflect.ValueOf(value))
+}
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type walker struct {
+	fn   WalkFn
+	seen map[visit]bool
+}
+
+func (walker *walker) walk(current path.P, value reflect.Value) {
+	switch value.Kind() {
+
+	case reflect.Interface:
+		if !value.IsNil() {
+			walker.walk(current, value.Elem())
+		}
+
+	case reflect.Ptr:
+		if value.IsNil() {
+			return
+		}
+
+		key := visit{value.Pointer(), value.Type()}
+		if walker.seen[key] {
+			return
+		}
+		walker.seen[key] = true
+
+		walker.walk(current, value.Elem())
+
+	case reflect.Struct:
+		for _, field := range Fields(value.Type()) {
+			fieldPath := join(current, field.Name)
+			fieldValue := value.FieldByName(field.Name)
+
+			walker.fn(fieldPath, field, fieldValue)
+			walker.walk(fieldPath, fieldValue)
+		}
+
+	case reflect.Map:
+		keys := make([]string, 0, value.Len())
+		values := make(map[string]reflect.Value)
+
+		for _, key := range value.MapKeys() {
+			str := fmt.Sprint(key.Interface())
+			keys = append(keys, str)
+			values[str] = value.MapIndex(key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			walker.walk(join(current, key), values[key])
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			walker.walk(join(current, strconv.Itoa(i)), value.Index(i))
+		}
+	}
+}
+
+// join returns a new path made of the given path followed by the given
+// components. Unlike append, the given path is never modified.
+func join(current path.P, components ...string) path.P {
+	result := make(path.P, 0, len(current)+len(components))
+	result = append(result, current...)
+	return append(result, components...)
+}
@@ -0,0 +1,13 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+// Command blueprint validates and renders blueprints and documents the
+// registered types. See the cli package for details.
+package main
+
+import (
+	"github.com/RAttab/goblueprint/blueprint/cli"
+
+	"os"
+)
+
+func main() { os.Exit(cli.Main(os.Args[1:], os.Stdout, os.Stderr)) }
@@ -0,0 +1,351 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+// Command blueprintgen generates a file which registers the types of a package
+// with the blueprint registry. It's meant to be invoked through go generate:
+//
+//	//go:generate go run github.com/RAttab/goblueprint/cmd/blueprintgen -annotated
+//
+// By default all the exported struct types of the package are registered. The
+// selection can be restricted to the types annotated with a
+// //blueprint:register comment (-annotated) or to the types implementing one
+// of the listed interfaces (-implements Handler,io.Closer). When both flags are
+// given, types matching either are registered. Interfaces are either declared
+// in the package or qualified by the path or name of an imported package.
+//
+// Types which the package already registers through a blueprint registration
+// call (eg. blueprint.Register(Type{}) in an init function) are skipped to avoid
+// duplicate registrations.
+//
+// In check mode (-check) the file is not written and the command exits with a
+// non-zero status if the existing file is missing or stale which makes it
+// suitable for CI.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Annotation marks the types to register when the -annotated flag is used.
+const Annotation = "//blueprint:register"
+
+// Options controls which types are registered by generate.
+type Options struct {
+	Annotated  bool
+	Implements []string
+	Output     string
+}
+
+func main() {
+	var opts Options
+	var implements string
+	var check bool
+
+	flag.BoolVar(&opts.Annotated, "annotated", false, "only register types annotated with "+Annotation)
+	flag.StringVar(&implements, "implements", "", "only register types implementing one of the comma separated interfaces")
+	flag.StringVar(&opts.Output, "output", "blueprint_register.go", "name of the generated file")
+	flag.BoolVar(&check, "check", false, "fail if the generated file is missing or stale instead of writing it")
+	flag.Parse()
+
+	if implements != "" {
+		opts.Implements = strings.Split(implements, ",")
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	if err := run(dir, opts, check); err != nil {
+		fmt.Fprintf(os.Stderr, "blueprintgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run writes the registration file for the package in the given directory or,
+// in check mode, returns an error if the existing file is missing or stale.
+func run(dir string, opts Options, check bool) error {
+	body, err := generate(dir, opts)
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(dir, opts.Output)
+
+	if check {
+		if old, err := os.ReadFile(file); err != nil || !bytes.Equal(old, body) {
+			return fmt.Errorf("'%s' is stale; run go generate", file)
+		}
+		return nil
+	}
+
+	return os.WriteFile(file, body, 0644)
+}
+
+// generate returns the content of the registration file for the package in
+// the given directory.
+func generate(dir string, opts Options) ([]byte, error) {
+	fset := token.NewFileSet()
+
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	name := pkg.Name
+
+	for _, file := range pkg.GoFiles {
+		if file == opts.Output {
+			continue
+		}
+
+		parsed, err := parser.ParseFile(fset, filepath.Join(dir, file), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, parsed)
+	}
+
+	annotated := make(map[string]bool)
+	registered := registeredTypes(files)
+	var candidates []string
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if _, ok := spec.Type.(*ast.StructType); !ok || !spec.Name.IsExported() || spec.TypeParams != nil {
+					continue
+				}
+
+				if registered[spec.Name.Name] {
+					continue
+				}
+
+				candidates = append(candidates, spec.Name.Name)
+
+				doc := spec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+
+				if hasAnnotation(doc) {
+					annotated[spec.Name.Name] = true
+				}
+			}
+		}
+	}
+
+	var ifaces []*types.Interface
+
+	if len(opts.Implements) > 0 {
+		config := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+
+		pkg, err := config.Check(name, fset, files, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to type check '%s': %s", dir, err)
+		}
+
+		for _, iface := range opts.Implements {
+			typ, err := lookupInterface(pkg, strings.TrimSpace(iface))
+			if err != nil {
+				return nil, err
+			}
+			ifaces = append(ifaces, typ)
+		}
+
+		var result []string
+		for _, candidate := range candidates {
+			if implementsAny(pkg.Scope().Lookup(candidate).Type(), ifaces) || (opts.Annotated && annotated[candidate]) {
+				result = append(result, candidate)
+			}
+		}
+		candidates = result
+
+	} else if opts.Annotated {
+		var result []string
+		for _, candidate := range candidates {
+			if annotated[candidate] {
+				result = append(result, candidate)
+			}
+		}
+		candidates = result
+	}
+
+	sort.Strings(candidates)
+
+	buffer := new(bytes.Buffer)
+	fmt.Fprintf(buffer, "// Code generated by blueprintgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(buffer, "package %s\n\n", name)
+
+	if len(candidates) > 0 {
+		fmt.Fprintf(buffer, "import \"github.com/RAttab/goblueprint/blueprint\"\n\n")
+		fmt.Fprintf(buffer, "func init() {\n")
+		for _, candidate := range candidates {
+			fmt.Fprintf(buffer, "\tblueprint.Register(%s{})\n", candidate)
+		}
+		fmt.Fprintf(buffer, "}\n")
+	}
+
+	return format.Source(buffer.Bytes())
+}
+
+// registerFuncs lists the blueprint functions whose first argument is a value
+// of the registered type.
+var registerFuncs = map[string]bool{
+	"Register":        true,
+	"TryRegister":     true,
+	"RegisterMeta":    true,
+	"TryRegisterMeta": true,
+}
+
+// registeredTypes returns the names of the types of the package which are
+// already registered through calls to the blueprint registration functions
+// (eg. blueprint.Register(Type{}) or blueprint.RegisterType[Type]()).
+func registeredTypes(files []*ast.File) map[string]bool {
+	registered := make(map[string]bool)
+
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			if index, ok := call.Fun.(*ast.IndexExpr); ok && funcName(index.X) == "RegisterType" {
+				if name := typeName(index.Index); name != "" {
+					registered[name] = true
+				}
+
+			} else if registerFuncs[funcName(call.Fun)] && len(call.Args) > 0 {
+				if name := typeName(call.Args[0]); name != "" {
+					registered[name] = true
+				}
+			}
+
+			return true
+		})
+	}
+
+	return registered
+}
+
+func funcName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	}
+	return ""
+}
+
+// typeName returns the name of the local type of the given expression (eg.
+// Type{}, &Type{}, new(Type) or (*Type)(nil)) or an empty string if it can't
+// be determined.
+func typeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+
+	case *ast.Ident:
+		return expr.Name
+
+	case *ast.CompositeLit:
+		return typeName(expr.Type)
+
+	case *ast.UnaryExpr:
+		return typeName(expr.X)
+
+	case *ast.StarExpr:
+		return typeName(expr.X)
+
+	case *ast.ParenExpr:
+		return typeName(expr.X)
+
+	case *ast.CallExpr:
+		if ident, ok := expr.Fun.(*ast.Ident); ok && ident.Name == "new" && len(expr.Args) == 1 {
+			return typeName(expr.Args[0])
+		}
+		if paren, ok := expr.Fun.(*ast.ParenExpr); ok {
+			return typeName(paren.X)
+		}
+	}
+
+	return ""
+}
+
+func hasAnnotation(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+
+	for _, comment := range doc.List {
+		if strings.TrimSpace(comment.Text) == Annotation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lookupInterface finds the given interface either in the given package or in
+// one of its imports if the name is qualified by a package name or path.
+func lookupInterface(pkg *types.Package, name string) (*types.Interface, error) {
+	scope := pkg.Scope()
+
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		scope = nil
+
+		for _, imported := range pkg.Imports() {
+			if imported.Path() == name[:i] || imported.Name() == name[:i] {
+				scope = imported.Scope()
+				break
+			}
+		}
+
+		if scope == nil {
+			return nil, fmt.Errorf("unknown package for interface '%s'", name)
+		}
+
+		name = name[i+1:]
+	}
+
+	obj := scope.Lookup(name)
+	if obj == nil {
+		return nil, fmt.Errorf("unknown interface '%s'", name)
+	}
+
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not an interface", name)
+	}
+
+	return iface, nil
+}
+
+func implementsAny(typ types.Type, ifaces []*types.Interface) bool {
+	for _, iface := range ifaces {
+		if types.Implements(typ, iface) || types.Implements(types.NewPointer(typ), iface) {
+			return true
+		}
+	}
+	return false
+}
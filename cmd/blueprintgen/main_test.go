@@ -0,0 +1,144 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const source = `package handlers
+
+import "io"
+
+type Handler interface{ Handle() }
+
+// Printer prints.
+//
+//blueprint:register
+type Printer struct{}
+
+func (*Printer) Handle() {}
+
+type Closer struct{}
+
+func (Closer) Close() error { return nil }
+
+type Other struct{}
+
+type Registered struct{}
+
+type Pointer struct{}
+
+type Generic struct{}
+
+type unexported struct{}
+
+func Register(interface{}) {}
+
+func RegisterType[T any]() {}
+
+func init() {
+	Register(Registered{})
+	Register((*Pointer)(nil))
+	RegisterType[Generic]()
+}
+
+var _ io.Closer = Closer{}
+`
+
+func CheckGenerate(t *testing.T, dir string, opts Options, exp ...string) {
+	body, err := generate(dir, opts)
+	if err != nil {
+		t.Errorf("FAIL(%+v): unexpected error: %s", opts, err)
+		return
+	}
+
+	var registered []string
+	for _, line := range strings.Split(string(body), "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "blueprint.Register(") {
+			registered = append(registered, strings.TrimSuffix(strings.TrimPrefix(line, "blueprint.Register("), "{})"))
+		}
+	}
+
+	if strings.Join(registered, ",") != strings.Join(exp, ",") {
+		t.Errorf("FAIL(%+v): registered %v != %v\n%s", opts, registered, exp, body)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "handlers.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stale output which would otherwise break the type checking.
+	if err := os.WriteFile(filepath.Join(dir, "blueprint_register.go"), []byte("package handlers\nvar x = Removed{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Generator programs excluded through build constraints are ignored.
+	generator := "//go:build ignore\n\npackage main\n\nfunc main() {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "gen.go"), []byte(generator), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := "blueprint_register.go"
+
+	CheckGenerate(t, dir, Options{Output: output}, "Closer", "Other", "Printer")
+	CheckGenerate(t, dir, Options{Output: output, Annotated: true}, "Printer")
+	CheckGenerate(t, dir, Options{Output: output, Implements: []string{"Handler"}}, "Printer")
+	CheckGenerate(t, dir, Options{Output: output, Implements: []string{"io.Closer"}}, "Closer")
+	CheckGenerate(t, dir, Options{Output: output, Implements: []string{"io.Closer"}, Annotated: true}, "Closer", "Printer")
+
+	if _, err := generate(dir, Options{Output: output, Implements: []string{"Printer"}}); err == nil {
+		t.Errorf("FAIL: expected error for non-interface")
+	}
+}
+
+func TestGenerate_TypeErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	body := "package broken\n\ntype A struct{ B Missing }\n"
+	if err := os.WriteFile(filepath.Join(dir, "broken.go"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{Output: "blueprint_register.go", Implements: []string{"io.Closer"}}
+	if _, err := generate(dir, opts); err == nil || !strings.Contains(err.Error(), "undefined: Missing") {
+		t.Errorf("FAIL: expected type check error got %v", err)
+	}
+}
+
+func TestRun_Check(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{Output: "blueprint_register.go"}
+
+	if err := os.WriteFile(filepath.Join(dir, "handlers.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run(dir, opts, true); err == nil || !strings.Contains(err.Error(), "is stale") {
+		t.Errorf("FAIL: expected stale error for missing file got %v", err)
+	}
+
+	if err := run(dir, opts, false); err != nil {
+		t.Fatalf("FAIL: unable to generate: %s", err)
+	}
+
+	if err := run(dir, opts, true); err != nil {
+		t.Errorf("FAIL: unexpected error for fresh file: %s", err)
+	}
+
+	stale := source + "\ntype Added struct{}\n"
+	if err := os.WriteFile(filepath.Join(dir, "handlers.go"), []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run(dir, opts, true); err == nil || !strings.Contains(err.Error(), "is stale") {
+		t.Errorf("FAIL: expected stale error after adding a type got %v", err)
+	}
+}